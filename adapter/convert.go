@@ -17,8 +17,12 @@
 package adapter
 
 import (
-	"dirpx.dev/derrors"
-	"dirpx.dev/derrors/apis"
+	"errors"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 )
 
 // ToDescriptor converts a domain-level error together with its resolved
@@ -41,26 +45,97 @@ func ToDescriptor(e *derrors.Error, st apis.Status) apis.ErrorDescriptor {
 }
 
 // ToView converts a domain-level error into a public ErrorView using the
-// resolved status. This function performs no automatic redaction or filtering;
-// it exposes exactly what the error instance contains.
+// resolved status.
 //
-// If the underlying error implements apis.DetailedError, its details are
-// copied into the view as-is. It is up to the caller or API layer to decide
-// whether to redact or filter sensitive fields.
-func ToView(e *derrors.Error, st apis.Status) apis.ErrorView {
+// The error's details are copied into the view, except entries stored under
+// or naming a key declared with derrors.KeySensitive (see
+// (*derrors.Error).PublicErrorDetails). Without options no further filtering is done; pass
+// WithRedactor to apply a redaction policy to the message and details.
+func ToView(e *derrors.Error, st apis.Status, opts ...Option) apis.ErrorView {
 	if e == nil {
 		return apis.ErrorView{}
 	}
+	o := buildOptions(opts)
 	v := apis.ErrorView{
 		Code:    string(e.Code),
 		Reason:  string(e.Reason),
-		Message: e.Message,
+		Message: RedactMessage(o.redactor, e.Code, e.Reason, e.Message),
+	}
+	// Propagate the public details; sensitivity is checked on both the
+	// Details keys and the projected fields.
+	if ds := RedactDetails(o.redactor, e.Code, e.PublicErrorDetails()); len(ds) > 0 {
+		v.Details = ds
+	}
+	return v
+}
+
+// ViewOf converts an arbitrary error into a public ErrorView using only the
+// contracts from derrors/apis, so *derrors.Error and third-party error types
+// take the same path.
+//
+// Resolution order:
+//
+//  1. the first apis.ViewProvider in the chain supplies the whole view;
+//  2. otherwise the view is assembled from apis.CodedError,
+//     apis.ReasonedError and apis.DetailedError found in the chain;
+//  3. errors without a code are reported as code.Internal with
+//     derrors.InternalMessage; their own text never reaches the view.
+//
+// Like ToView, it applies a redaction policy only when WithRedactor is given.
+func ViewOf(err error, opts ...Option) apis.ErrorView {
+	if err == nil {
+		return apis.ErrorView{}
+	}
+	o := buildOptions(opts)
+	var vp apis.ViewProvider
+	if errors.As(err, &vp) {
+		return redactView(o.redactor, vp.ErrorView())
 	}
-	// If the error provides structured details, propagate them directly.
-	if de, ok := any(e).(apis.DetailedError); ok {
-		if ds := de.ErrorDetails(); len(ds) > 0 {
+
+	v := apis.ErrorView{Code: string(code.Internal), Message: derrors.InternalMessage}
+	var ce apis.CodedError
+	if errors.As(err, &ce) {
+		if c := ce.ErrorCode(); c != "" {
+			v.Code = c
+			// The coded error's own text, not err's: outer wrappers
+			// (fmt.Errorf("...: %w")) may add internal context.
+			v.Message = ce.Error()
+		}
+	}
+	var re apis.ReasonedError
+	if errors.As(err, &re) {
+		v.Reason = re.ErrorReason()
+	}
+	var de apis.DetailedError
+	if errors.As(err, &de) {
+		if ds := derrors.PublicDetails(de.ErrorDetails()); len(ds) > 0 {
 			v.Details = ds
 		}
 	}
+	return redactView(o.redactor, v)
+}
+
+// redactView applies r to an already assembled view.
+func redactView(r apis.Redactor, v apis.ErrorView) apis.ErrorView {
+	if r == nil {
+		return v
+	}
+	c := code.Code(v.Code)
+	v.Message = r.RedactMessage(c, reason.Reason(v.Reason), v.Message)
+	v.Details = RedactDetails(r, c, v.Details)
 	return v
 }
+
+// CauseOf returns the direct cause of err.
+//
+// It prefers apis.CausedError, which *derrors.Error implements, and falls
+// back to errors.Unwrap for other error types.
+func CauseOf(err error) error {
+	if err == nil {
+		return nil
+	}
+	if ce, ok := err.(apis.CausedError); ok {
+		return ce.Cause()
+	}
+	return errors.Unwrap(err)
+}
@@ -0,0 +1,145 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package adapter
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/redact"
+)
+
+// thirdParty is a foreign error type that implements the apis contracts.
+type thirdParty struct{}
+
+func (thirdParty) Error() string       { return "quota exceeded" }
+func (thirdParty) ErrorCode() string   { return string(code.RateLimited) }
+func (thirdParty) ErrorReason() string { return "billing.quota" }
+func (thirdParty) ErrorDetails() []apis.Detail {
+	return []apis.Detail{
+		{Field: "plan", Info: map[string]string{"value": "free"}},
+		{Field: secretKey.Name(), Info: map[string]string{"value": "hunter2"}},
+	}
+}
+
+var secretKey = derrors.NewKey[string]("adapter_test_secret", derrors.KeySensitive())
+
+func TestViewOf_ThirdParty(t *testing.T) {
+	err := fmt.Errorf("billing svc at 10.0.0.7: %w", thirdParty{})
+	v := ViewOf(err)
+	if v.Code != string(code.RateLimited) || v.Reason != "billing.quota" {
+		t.Fatalf("view = %+v", v)
+	}
+	// Only the coded error's text is used, not the wrapper's.
+	if v.Message != "quota exceeded" {
+		t.Fatalf("message = %q", v.Message)
+	}
+	if len(v.Details) != 1 || v.Details[0].Field != "plan" {
+		t.Fatalf("details = %+v", v.Details)
+	}
+}
+
+func TestViewOf_Uncoded(t *testing.T) {
+	v := ViewOf(errors.New("dial tcp 10.0.0.7:5432: connection refused"))
+	if v.Code != string(code.Internal) || v.Message != derrors.InternalMessage {
+		t.Fatalf("view = %+v", v)
+	}
+}
+
+func TestViewOf_Error(t *testing.T) {
+	e := derrors.E(code.NotFound, "user not found", derrors.WithReasonOption("user.missing"))
+	v := ViewOf(fmt.Errorf("lookup: %w", e))
+	want := ToView(e, apis.Status{})
+	if v.Code != want.Code || v.Reason != want.Reason || v.Message != want.Message {
+		t.Fatalf("ViewOf = %+v, ToView = %+v", v, want)
+	}
+}
+
+func TestCauseOf(t *testing.T) {
+	root := errors.New("root")
+	if got := CauseOf(derrors.E(code.Internal, "boom").WithCause(root)); got != root {
+		t.Fatalf("CauseOf(*Error) = %v", got)
+	}
+	if got := CauseOf(fmt.Errorf("wrap: %w", root)); got != root {
+		t.Fatalf("CauseOf(wrapped) = %v", got)
+	}
+	if got := CauseOf(root); got != nil {
+		t.Fatalf("CauseOf(root) = %v", got)
+	}
+}
+
+var (
+	requestKey = derrors.NewKey[string]("adapter_test_request", derrors.KeyPublic())
+	tokenKey   = derrors.NewKey[string]("adapter_test_token", derrors.KeySensitive())
+)
+
+// serverFailure is a server-fault error carrying a public, a plain and a
+// sensitive detail.
+func serverFailure() *derrors.Error {
+	e := derrors.E(code.Internal, "pq: password auth failed for 10.0.0.7").WithDetail("host", "10.0.0.7")
+	e = derrors.WithValue(e, tokenKey, "hunter2")
+	return derrors.WithValue(e, requestKey, "req-1")
+}
+
+func detailFields(ds []apis.Detail) []string {
+	var out []string
+	for _, d := range ds {
+		out = append(out, d.Field)
+	}
+	return out
+}
+
+func TestToView_Profiles(t *testing.T) {
+	e := serverFailure()
+
+	pub := ToView(e, apis.Status{}, WithRedactor(redact.Public()))
+	if pub.Message != redact.DefaultGenericMessage {
+		t.Fatalf("public message = %q", pub.Message)
+	}
+	if got := detailFields(pub.Details); len(got) != 1 || got[0] != requestKey.Name() {
+		t.Fatalf("public details = %v", got)
+	}
+
+	in := ToView(e, apis.Status{}, WithRedactor(redact.Internal()))
+	if in.Message != e.Message {
+		t.Fatalf("internal message = %q", in.Message)
+	}
+	if got := detailFields(in.Details); len(got) != 2 || got[0] != requestKey.Name() || got[1] != "host" {
+		t.Fatalf("internal details = %v", got)
+	}
+}
+
+func TestViewOf_Profiles(t *testing.T) {
+	err := fmt.Errorf("handler: %w", serverFailure())
+
+	pub := ViewOf(err, WithRedactor(redact.Public()))
+	if pub.Message != redact.DefaultGenericMessage {
+		t.Fatalf("public message = %q", pub.Message)
+	}
+	if got := detailFields(pub.Details); len(got) != 1 || got[0] != requestKey.Name() {
+		t.Fatalf("public details = %v", got)
+	}
+
+	in := ViewOf(err, WithRedactor(redact.Internal()))
+	if got := detailFields(in.Details); len(got) != 2 {
+		t.Fatalf("internal details = %v", got)
+	}
+}
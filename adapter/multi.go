@@ -0,0 +1,89 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package adapter
+
+import (
+	"dirpx.dev/derrors/v2"
+	derrorsv1 "dirpx.dev/derrors/v2/api/derrors/v1"
+)
+
+// Violations projects the children of an aggregate error (see derrors.Join)
+// into wire violations, one per child, in order. It returns nil when e is not
+// an aggregate.
+//
+// Each violation carries the child's derrors.FieldKey value (if any), its
+// reason (or its code when the reason is empty) and its message. With
+// WithRedactor, each message is redacted according to the child's code.
+func Violations(e *derrors.Error, opts ...Option) []*derrorsv1.Violation {
+	children := multiChildren(e)
+	if len(children) == 0 {
+		return nil
+	}
+	o := buildOptions(opts)
+	out := make([]*derrorsv1.Violation, 0, len(children))
+	for _, c := range children {
+		field, _ := derrors.Value(c, derrors.FieldKey)
+		r := string(c.Reason)
+		if r == "" {
+			r = string(c.Code)
+		}
+		msg := RedactMessage(o.redactor, c.Code, c.Reason, c.Message)
+		out = append(out, &derrorsv1.Violation{Field: field, Reason: r, Message: msg})
+	}
+	return out
+}
+
+// Causes projects the underlying causes of an aggregate error's children into
+// a shallow wire cause list. Children without an Err are skipped. It returns
+// nil when e is not an aggregate.
+//
+// Each entry uses the child's reason (or code) as Type and the text of the
+// child's Err as Message. Causes are opt-in: it returns nil unless a
+// redactor set with WithRedactor exposes causes for e's code.
+func Causes(e *derrors.Error, opts ...Option) []*derrorsv1.Cause {
+	children := multiChildren(e)
+	if len(children) == 0 {
+		return nil
+	}
+	if !ExposeCauses(buildOptions(opts).redactor, e.Code) {
+		return nil
+	}
+	var out []*derrorsv1.Cause
+	for _, c := range children {
+		if c.Err == nil {
+			continue
+		}
+		typ := string(c.Reason)
+		if typ == "" {
+			typ = string(c.Code)
+		}
+		out = append(out, &derrorsv1.Cause{Type: typ, Message: c.Err.Error()})
+	}
+	return out
+}
+
+// multiChildren returns the children when e directly wraps a *derrors.Multi.
+func multiChildren(e *derrors.Error) []*derrors.Error {
+	if e == nil {
+		return nil
+	}
+	m, ok := e.Err.(*derrors.Multi)
+	if !ok {
+		return nil
+	}
+	return m.Errors()
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package adapter
+
+import (
+	"errors"
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/redact"
+)
+
+func joined() *derrors.Error {
+	name := derrors.E(code.Invalid, "name is required", derrors.WithValueOption(derrors.FieldKey, "name")).
+		WithReason("user.name.missing").
+		WithCause(errors.New("empty string"))
+	age := derrors.E(code.Invalid, "age must be positive", derrors.WithValueOption(derrors.FieldKey, "age"))
+	return derrors.Join(name, age)
+}
+
+func TestViolations_Join(t *testing.T) {
+	vs := Violations(joined())
+	if len(vs) != 2 {
+		t.Fatalf("violations = %v", vs)
+	}
+	if vs[0].Field != "name" || vs[0].Reason != "user.name.missing" || vs[0].Message != "name is required" {
+		t.Fatalf("first violation = %v", vs[0])
+	}
+	if vs[1].Field != "age" || vs[1].Reason != string(code.Invalid) {
+		t.Fatalf("second violation = %v", vs[1])
+	}
+	if Violations(derrors.E(code.Invalid, "single")) != nil {
+		t.Fatal("non-aggregate must have no violations")
+	}
+}
+
+func TestCauses_OptIn(t *testing.T) {
+	e := joined()
+	if cs := Causes(e); cs != nil {
+		t.Fatalf("causes without a redactor = %v", cs)
+	}
+	if cs := Causes(e, WithRedactor(redact.Public())); cs != nil {
+		t.Fatalf("public causes = %v", cs)
+	}
+	cs := Causes(e, WithRedactor(redact.Internal()))
+	if len(cs) != 1 || cs[0].Type != "user.name.missing" || cs[0].Message != "empty string" {
+		t.Fatalf("internal causes = %v", cs)
+	}
+}
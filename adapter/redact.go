@@ -0,0 +1,76 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package adapter
+
+import (
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Option configures how adapter functions project errors.
+type Option func(*options)
+
+type options struct {
+	redactor apis.Redactor
+}
+
+// WithRedactor applies r to messages, details and causes before they are
+// exposed. A nil Redactor disables redaction.
+func WithRedactor(r apis.Redactor) Option {
+	return func(o *options) { o.redactor = r }
+}
+
+func buildOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// RedactMessage returns msg as r allows it to be exposed. A nil r returns
+// msg unchanged.
+func RedactMessage(r apis.Redactor, c code.Code, rsn reason.Reason, msg string) string {
+	if r == nil {
+		return msg
+	}
+	return r.RedactMessage(c, rsn, msg)
+}
+
+// RedactDetails returns the details of an error with code c as r allows them
+// to be exposed. A nil r returns ds unchanged.
+func RedactDetails(r apis.Redactor, c code.Code, ds []apis.Detail) []apis.Detail {
+	if r == nil || len(ds) == 0 {
+		return ds
+	}
+	var out []apis.Detail
+	for _, d := range ds {
+		if d, ok := r.RedactDetail(c, d); ok {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// ExposeCauses reports whether r allows causes of an error with code c to be
+// exposed. Causes are opt-in: a nil r does not allow them.
+func ExposeCauses(r apis.Redactor, c code.Code) bool {
+	return r != nil && r.ExposeCauses(c)
+}
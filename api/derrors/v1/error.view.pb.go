@@ -38,7 +38,9 @@ type ErrorView struct {
 	// Human-facing links (docs/support/etc.)
 	Links []*Link `protobuf:"bytes,30,rep,name=links,proto3" json:"links,omitempty"`
 	// Validation violations (aka "fields" in the schema)
-	Fields        []*Violation `protobuf:"bytes,40,rep,name=fields,proto3" json:"fields,omitempty"`
+	Fields []*Violation `protobuf:"bytes,40,rep,name=fields,proto3" json:"fields,omitempty"`
+	// Shallow causes of aggregate errors (derrors.Join)
+	Causes        []*Cause `protobuf:"bytes,50,rep,name=causes,proto3" json:"causes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -136,12 +138,19 @@ func (x *ErrorView) GetFields() []*Violation {
 	return nil
 }
 
+func (x *ErrorView) GetCauses() []*Cause {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
 var File_derrors_v1_error_view_proto protoreflect.FileDescriptor
 
 const file_derrors_v1_error_view_proto_rawDesc = "" +
 	"\n" +
 	"\x1bderrors/v1/error.view.proto\x12\n" +
-	"derrors.v1\x1a\x16derrors/v1/error.proto\"\xb2\x02\n" +
+	"derrors.v1\x1a\x16derrors/v1/error.proto\"\xdd\x02\n" +
 	"\tErrorView\x12\x12\n" +
 	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12\x16\n" +
@@ -152,7 +161,8 @@ const file_derrors_v1_error_view_proto_rawDesc = "" +
 	"\aspan_id\x18\f \x01(\tR\aspan_id\x120\n" +
 	"\x13retry_after_seconds\x18\x14 \x01(\x05R\x13retry_after_seconds\x12&\n" +
 	"\x05links\x18\x1e \x03(\v2\x10.derrors.v1.LinkR\x05links\x12-\n" +
-	"\x06fields\x18( \x03(\v2\x15.derrors.v1.ViolationR\x06fieldsB$Z\"dirpx.dev/api/derrors/v1;derrorsv1b\x06proto3"
+	"\x06fields\x18( \x03(\v2\x15.derrors.v1.ViolationR\x06fields\x12)\n" +
+	"\x06causes\x182 \x03(\v2\x11.derrors.v1.CauseR\x06causesB$Z\"dirpx.dev/api/derrors/v1;derrorsv1b\x06proto3"
 
 var (
 	file_derrors_v1_error_view_proto_rawDescOnce sync.Once
@@ -171,15 +181,17 @@ var file_derrors_v1_error_view_proto_goTypes = []any{
 	(*ErrorView)(nil), // 0: derrors.v1.ErrorView
 	(*Link)(nil),      // 1: derrors.v1.Link
 	(*Violation)(nil), // 2: derrors.v1.Violation
+	(*Cause)(nil),     // 3: derrors.v1.Cause
 }
 var file_derrors_v1_error_view_proto_depIdxs = []int32{
 	1, // 0: derrors.v1.ErrorView.links:type_name -> derrors.v1.Link
 	2, // 1: derrors.v1.ErrorView.fields:type_name -> derrors.v1.Violation
-	2, // [2:2] is the sub-list for method output_type
-	2, // [2:2] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	3, // 2: derrors.v1.ErrorView.causes:type_name -> derrors.v1.Cause
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_derrors_v1_error_view_proto_init() }
@@ -0,0 +1,71 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apis
+
+import (
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Classification is the outcome of classifying an arbitrary Go error into
+// the derrors model.
+type Classification struct {
+	// Code is the resolved error code. It MUST be non-empty.
+	Code code.Code
+
+	// Reason optionally refines the code, e.g. "context.deadline_exceeded".
+	Reason reason.Reason
+
+	// Message is an optional human-readable message. When empty, callers
+	// typically fall back to the original error text.
+	Message string
+}
+
+// Classifier maps errors that do not carry a derrors code (context errors,
+// io/fs errors, driver errors, transport errors, ...) onto a Classification.
+//
+// Implementations MUST be safe for concurrent use and SHOULD be cheap: they
+// run on error paths of every request. They SHOULD inspect the whole chain
+// with errors.Is / errors.As rather than comparing err directly.
+type Classifier interface {
+	// Classify returns the classification for err and true, or false when
+	// the classifier does not recognize err.
+	Classify(err error) (Classification, bool)
+}
+
+// ClassifierFunc adapts a plain function to the Classifier interface.
+type ClassifierFunc func(err error) (Classification, bool)
+
+// Classify implements Classifier.
+func (f ClassifierFunc) Classify(err error) (Classification, bool) { return f(err) }
+
+// Classifiers is an ordered chain of classifiers; the first one that
+// recognizes the error wins.
+type Classifiers []Classifier
+
+// Classify implements Classifier by trying each element in order.
+func (cs Classifiers) Classify(err error) (Classification, bool) {
+	for _, c := range cs {
+		if c == nil {
+			continue
+		}
+		if cl, ok := c.Classify(err); ok {
+			return cl, true
+		}
+	}
+	return Classification{}, false
+}
@@ -0,0 +1,37 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apis
+
+import (
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Localizer resolves the message template for an error kind in a given
+// locale.
+//
+// Templates may contain named placeholders such as "{resource}"; callers
+// render them with the error details (see package i18n). The locale is a
+// BCP 47 tag such as "en" or "pt-BR"; an empty locale asks for the
+// implementation's default language.
+//
+// Implementations MUST be safe for concurrent use.
+type Localizer interface {
+	// Localize returns the template for (c, r) in locale, or false when none
+	// is known.
+	Localize(c code.Code, r reason.Reason, locale string) (string, bool)
+}
@@ -17,8 +17,8 @@
 package apis
 
 import (
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 	"google.golang.org/grpc/codes"
 )
 
@@ -48,3 +48,78 @@ type Status struct {
 	HTTP int        // Resolved HTTP status code (net/http compatible).
 	GRPC codes.Code // Resolved gRPC status code.
 }
+
+// ReverseMapper is implemented by Mappers that can also derive a logical code
+// from a transport status, e.g. to turn a downstream response into a
+// derrors.Error on the client side.
+//
+// Mappers built by dirpx.dev/derrors/v2/mapper implement it:
+//
+//	if rm, ok := m.(apis.ReverseMapper); ok {
+//	    c := rm.FromHTTP(resp.StatusCode)
+//	}
+type ReverseMapper interface {
+	// FromHTTP returns the code whose code-level rule produces status.
+	// It returns code.Empty for statuses below 400.
+	FromHTTP(status int) code.Code
+
+	// FromGRPC returns the code whose code-level rule produces c.
+	// It returns code.Empty for codes.OK.
+	FromGRPC(c codes.Code) code.Code
+}
+
+// Explainer is implemented by Mappers that can report how they resolve a
+// (code, reason) pair as data rather than text, e.g. for admin endpoints
+// and tests. Mappers built by dirpx.dev/derrors/v2/mapper implement it, and
+// their Explain output is a rendering of Resolve.
+type Explainer interface {
+	// Resolve returns the decision for both transports, including the rules
+	// that matched but lost. It is a diagnostic call and may allocate.
+	Resolve(c code.Code, r reason.Reason) Resolution
+}
+
+// Tier names the kind of rule that produced a status.
+type Tier string
+
+const (
+	TierOverride Tier = "override" // per-code override
+	TierExact    Tier = "exact"    // per-code rule for one exact reason
+	TierPrefix   Tier = "prefix"   // per-code reason-prefix rule
+	TierDefault  Tier = "default"  // per-code default
+	TierFallback Tier = "fallback" // global fallback for unmapped codes
+)
+
+// Resolution is the structured form of Mapper.Explain.
+type Resolution struct {
+	Code   code.Code     `json:"code"`
+	Reason reason.Reason `json:"reason"`
+
+	// Precedence names the rule order in effect, e.g. "prefix-first".
+	Precedence string `json:"precedence,omitempty"`
+
+	HTTP Decision[int]        `json:"http"`
+	GRPC Decision[codes.Code] `json:"grpc"`
+}
+
+// Status returns the resolved statuses, as Mapper.Status would.
+func (r Resolution) Status() Status {
+	return Status{HTTP: r.HTTP.Value, GRPC: r.GRPC.Value}
+}
+
+// Decision is the outcome for one transport: the winning rule and the
+// candidates that also matched but ranked lower, best first.
+type Decision[S any] struct {
+	Tier    Tier   `json:"tier"`
+	Pattern string `json:"pattern,omitempty"`
+	Value   S      `json:"value"`
+
+	Lost []Candidate[S] `json:"lost,omitempty"`
+}
+
+// Candidate is a rule that matched a (code, reason) pair. Pattern is set for
+// exact and prefix rules.
+type Candidate[S any] struct {
+	Tier    Tier   `json:"tier"`
+	Pattern string `json:"pattern,omitempty"`
+	Value   S      `json:"value"`
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apis
+
+import (
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Redactor decides what part of an error may leave the process at a
+// transport boundary (HTTP response, gRPC status, public view).
+//
+// Adapters call it right before serialization; the error value itself is
+// never modified. Implementations MUST be safe for concurrent use and SHOULD
+// be cheap, since they run on every error response.
+type Redactor interface {
+	// RedactMessage returns the message to expose for an error with the
+	// given code and reason. Returning msg unchanged keeps it as-is.
+	RedactMessage(c code.Code, r reason.Reason, msg string) string
+
+	// RedactDetail returns the detail to expose for an error with the given
+	// code, or false when the detail must be dropped entirely.
+	RedactDetail(c code.Code, d Detail) (Detail, bool)
+
+	// ExposeCauses reports whether technical causes (cause chains, children
+	// causes of aggregates) may be exposed for an error with the given code.
+	ExposeCauses(c code.Code) bool
+}
@@ -0,0 +1,177 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package catalog
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/i18n"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// ErrDuplicateKind is returned by Register when a kind with the same
+// (code, reason) pair is already registered.
+var ErrDuplicateKind = errors.New("catalog: duplicate kind")
+
+// Kind is the declaration of one error kind.
+type Kind struct {
+	// Code is the canonical error code. It MUST be valid and non-empty.
+	Code code.Code `json:"code"`
+
+	// Reason optionally refines the code. It MUST be valid when set.
+	Reason reason.Reason `json:"reason,omitempty"`
+
+	// Message is the default human-readable message, used when an instance
+	// does not provide its own. It may contain "{name}" placeholders that
+	// are filled from the error details.
+	Message string `json:"message,omitempty"`
+
+	// DocURL points to the documentation of this kind.
+	DocURL string `json:"doc_url,omitempty"`
+
+	// Retryable tells clients whether retrying the same request may succeed.
+	Retryable bool `json:"retryable,omitempty"`
+
+	// Owner is the team or component responsible for this kind.
+	Owner string `json:"owner,omitempty"`
+}
+
+// Descriptor returns the apis.ErrorDescriptor view of k. Transport statuses
+// are left unspecified; they are the mapper's business.
+func (k Kind) Descriptor() apis.ErrorDescriptor {
+	return apis.ErrorDescriptor{
+		Code:    string(k.Code),
+		Reason:  string(k.Reason),
+		Message: k.Message,
+	}
+}
+
+// New creates an error of kind k. Options are applied after Code and Reason
+// are set; if no option provides a message, k.Message is used as a template
+// and rendered with the error details (see i18n.Render):
+//
+//	k := catalog.Kind{Code: code.QuotaExceeded, Message: "quota {resource} exceeded ({limit})"}
+//	catalog.New(k, derrors.WithDetailOption("resource", "cpu"), derrors.WithDetailOption("limit", 8))
+//	// quota_exceeded: quota cpu exceeded (8)
+func New(k Kind, opts ...derrors.Option) *derrors.Error {
+	// Skip New itself, so captured stacks start at the caller.
+	e := derrors.ESkip(1, k.Code, "", append([]derrors.Option{derrors.WithReasonOption(k.Reason)}, opts...)...)
+	if e.Message == "" {
+		e = e.WithMessage(i18n.Render(k.Message, i18n.Params(e)))
+	}
+	return e
+}
+
+// key identifies a kind inside a Catalog.
+type key struct {
+	code   code.Code
+	reason reason.Reason
+}
+
+// Catalog is a concurrency-safe registry of kinds. The zero value is an
+// empty catalog ready to use.
+type Catalog struct {
+	mu    sync.RWMutex
+	kinds map[key]Kind
+}
+
+// Default is the process-wide catalog used by Define.
+var Default = &Catalog{}
+
+// Define registers k in Default and returns the normalized kind.
+// It panics on invalid or duplicate kinds, which makes it suitable for
+// package-level var declarations.
+func Define(k Kind) Kind { return Default.MustRegister(k) }
+
+// Register validates k, normalizes its code and reason, and adds it to the
+// catalog. It returns the stored kind.
+//
+// It fails when the code or reason is invalid, or with ErrDuplicateKind when
+// the (code, reason) pair is already registered.
+func (c *Catalog) Register(k Kind) (Kind, error) {
+	cd, err := code.Parse(string(k.Code))
+	if err != nil {
+		return Kind{}, fmt.Errorf("catalog: code %q: %w", k.Code, err)
+	}
+	r, err := reason.Parse(string(k.Reason))
+	if err != nil {
+		return Kind{}, fmt.Errorf("catalog: reason %q: %w", k.Reason, err)
+	}
+	k.Code, k.Reason = cd, r
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := key{cd, r}
+	if _, ok := c.kinds[id]; ok {
+		return Kind{}, fmt.Errorf("%w: %s:%s", ErrDuplicateKind, cd, r)
+	}
+	if c.kinds == nil {
+		c.kinds = make(map[key]Kind)
+	}
+	c.kinds[id] = k
+	return k, nil
+}
+
+// MustRegister is the panic-on-error variant of Register.
+func (c *Catalog) MustRegister(k Kind) Kind {
+	k, err := c.Register(k)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// Lookup returns the kind registered for the exact (code, reason) pair.
+func (c *Catalog) Lookup(cd code.Code, r reason.Reason) (Kind, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	k, ok := c.kinds[key{cd, r}]
+	return k, ok
+}
+
+// Kinds returns all registered kinds ordered by code, then reason.
+func (c *Catalog) Kinds() []Kind {
+	c.mu.RLock()
+	out := make([]Kind, 0, len(c.kinds))
+	for _, k := range c.kinds {
+		out = append(out, k)
+	}
+	c.mu.RUnlock()
+	slices.SortFunc(out, func(a, b Kind) int {
+		return cmp.Or(cmp.Compare(a.Code, b.Code), cmp.Compare(a.Reason, b.Reason))
+	})
+	return out
+}
+
+// MarshalJSON implements json.Marshaler. The document has the shape
+//
+//	{"kinds": [{"code": "...", "reason": "...", "message": "...", ...}]}
+//
+// with kinds in the order of Kinds.
+func (c *Catalog) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kinds []Kind `json:"kinds"`
+	}{c.Kinds()})
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/code"
+)
+
+func TestRegister_NormalizesAndRejectsDuplicates(t *testing.T) {
+	var c Catalog
+	k, err := c.Register(Kind{Code: "Not-Found", Reason: "User/Missing", Message: "user not found"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if k.Code != code.NotFound || k.Reason != "user.missing" {
+		t.Fatalf("not normalized: %+v", k)
+	}
+	if _, err := c.Register(Kind{Code: code.NotFound, Reason: "user.missing"}); !errors.Is(err, ErrDuplicateKind) {
+		t.Fatalf("duplicate: got %v", err)
+	}
+	if _, err := c.Register(Kind{Code: code.NotFound}); err != nil {
+		t.Fatalf("same code without reason must be allowed: %v", err)
+	}
+	if _, err := c.Register(Kind{Code: "x"}); err == nil {
+		t.Fatal("invalid code must be rejected")
+	}
+	if _, err := c.Register(Kind{Code: code.Invalid, Reason: "Bad..Reason"}); err == nil {
+		t.Fatal("invalid reason must be rejected")
+	}
+	if got, ok := c.Lookup(code.NotFound, "user.missing"); !ok || got.Message != "user not found" {
+		t.Fatalf("Lookup = %+v, %v", got, ok)
+	}
+}
+
+func TestMustRegister_Panics(t *testing.T) {
+	var c Catalog
+	c.MustRegister(Kind{Code: code.Conflict})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate")
+		}
+	}()
+	c.MustRegister(Kind{Code: code.Conflict})
+}
+
+func TestNew_FillsMessage(t *testing.T) {
+	k := Kind{Code: code.NotFound, Reason: "user.missing", Message: "user not found"}
+
+	e := New(k)
+	if e.Code != code.NotFound || e.Reason != "user.missing" || e.Message != "user not found" {
+		t.Fatalf("New = %+v", e)
+	}
+	e = New(k, derrors.WithMessageOption("user 42 not found"), derrors.WithDetailOption("id", 42))
+	if e.Message != "user 42 not found" || e.Details["id"] != 42 {
+		t.Fatalf("New with options = %+v", e)
+	}
+}
+
+func TestNew_StackStartsAtCaller(t *testing.T) {
+	k := Kind{Code: code.Internal, Message: "boom"}
+	check := func(e *derrors.Error) {
+		t.Helper()
+		frames := e.StackTrace()
+		if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestNew_StackStartsAtCaller") {
+			t.Fatalf("first frame must be the caller of New, got %+v", frames)
+		}
+	}
+
+	check(New(k, derrors.WithStackOption()))
+
+	derrors.EnableStacks(8)
+	defer derrors.EnableStacks(0)
+	check(New(k))
+}
+
+func TestMarshalJSON_Sorted(t *testing.T) {
+	var c Catalog
+	c.MustRegister(Kind{Code: code.NotFound, Reason: "user.missing", Owner: "identity"})
+	c.MustRegister(Kind{Code: code.Conflict, Retryable: true})
+	c.MustRegister(Kind{Code: code.NotFound, DocURL: "https://example.com/nf"})
+
+	b, err := json.Marshal(&c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"kinds":[` +
+		`{"code":"conflict","retryable":true},` +
+		`{"code":"not_found","doc_url":"https://example.com/nf"},` +
+		`{"code":"not_found","reason":"user.missing","owner":"identity"}]}`
+	if string(b) != want {
+		t.Fatalf("got  %s\nwant %s", b, want)
+	}
+}
+
+func TestNew_RendersTemplate(t *testing.T) {
+	k := Kind{Code: code.QuotaExceeded, Message: "quota {resource} exceeded ({limit})"}
+	e := New(k, derrors.WithDetailOption("resource", "cpu"), derrors.WithDetailOption("limit", 8))
+	if e.Message != "quota cpu exceeded (8)" {
+		t.Fatalf("Message = %q", e.Message)
+	}
+}
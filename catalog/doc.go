@@ -0,0 +1,38 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package catalog declares error kinds once and creates errors from them.
+//
+// A Kind bundles everything that is known about a class of errors up front:
+// its code and reason, a default message, a documentation link, whether it is
+// retryable and which team owns it. Kinds are registered in a Catalog, which
+// validates them with code.Parse / reason.Parse and rejects duplicate
+// (code, reason) pairs, so conflicts surface at program start-up:
+//
+//	var ErrUserMissing = catalog.Define(catalog.Kind{
+//	    Code:    code.NotFound,
+//	    Reason:  "user.missing",
+//	    Message: "user not found",
+//	    DocURL:  "https://docs.example.com/errors/user-missing",
+//	    Owner:   "identity",
+//	})
+//
+//	return catalog.New(ErrUserMissing, derrors.WithCauseOption(err))
+//
+// The whole catalog can be exported as JSON for API documentation:
+//
+//	b, _ := json.MarshalIndent(catalog.Default, "", "  ")
+package catalog
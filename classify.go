@@ -0,0 +1,168 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Reasons assigned by the built-in classification rules.
+var (
+	ReasonContextCanceled         = reason.MustParse("context.canceled")
+	ReasonContextDeadlineExceeded = reason.MustParse("context.deadline_exceeded")
+	ReasonFSNotExist              = reason.MustParse("fs.not_exist")
+	ReasonFSExist                 = reason.MustParse("fs.exist")
+	ReasonFSPermission            = reason.MustParse("fs.permission")
+	ReasonSQLNoRows               = reason.MustParse("sql.no_rows")
+	ReasonSQLConnDone             = reason.MustParse("sql.conn_done")
+	ReasonNetworkTimeout          = reason.MustParse("network.timeout")
+	ReasonNetworkError            = reason.MustParse("network.error")
+)
+
+var (
+	// registered holds user classifiers as an immutable, copy-on-write
+	// snapshot so that Classify never takes a lock.
+	registered atomic.Pointer[apis.Classifiers]
+	// registerMu serializes writers of registered.
+	registerMu sync.Mutex
+)
+
+// RegisterClassifier adds c to the global classification chain used by
+// Classify. User classifiers run in registration order, before the built-in
+// rules, so they can refine or replace them.
+//
+// It is intended to be called during program initialization.
+func RegisterClassifier(c apis.Classifier) {
+	if c == nil {
+		return
+	}
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	var next apis.Classifiers
+	if cur := registered.Load(); cur != nil {
+		next = make(apis.Classifiers, 0, len(*cur)+1)
+		next = append(next, *cur...)
+	}
+	next = append(next, c)
+	registered.Store(&next)
+}
+
+// Classify converts an arbitrary error into a *Error.
+//
+//   - nil yields nil;
+//   - if the chain already contains a *Error, that error is returned as-is;
+//   - otherwise registered classifiers run first, then the built-in rules;
+//   - unrecognized errors become code.Internal with InternalMessage.
+//
+// The original error is always kept as Err. The built-in rules use the fixed
+// messages below, since paths, hosts and addresses in the original text must
+// not reach clients. For user classifiers the original text is used as the
+// Message unless the classifier provides one. The text of unrecognized errors
+// is never used, since nothing is known about what it reveals.
+//
+// Built-in rules:
+//
+//	context.Canceled          -> canceled    / context.canceled          "canceled"
+//	context.DeadlineExceeded  -> timeout     / context.deadline_exceeded "deadline exceeded"
+//	fs.ErrNotExist            -> not_found   / fs.not_exist              "not found"
+//	fs.ErrExist               -> already_exists / fs.exist               "already exists"
+//	fs.ErrPermission          -> permission_denied / fs.permission       "permission denied"
+//	sql.ErrNoRows             -> not_found   / sql.no_rows               "not found"
+//	sql.ErrConnDone           -> unavailable / sql.conn_done             "database unavailable"
+//	net.Error with Timeout()  -> timeout     / network.timeout           "network timeout"
+//	other net.Error           -> unavailable / network.error             "network error"
+func Classify(err error) *Error {
+	return ClassifyWith(err)
+}
+
+// ClassifyWith is like Classify but tries the given classifiers before the
+// global chain. Transport adapters use it to plug in protocol-specific rules
+// (for example grpcx.StatusClassifier) without registering them globally.
+func ClassifyWith(err error, extra ...apis.Classifier) *Error {
+	if err == nil {
+		return nil
+	}
+	var de *Error
+	if errors.As(err, &de) {
+		return de
+	}
+
+	if cl, ok := apis.Classifiers(extra).Classify(err); ok {
+		return classified(err, cl)
+	}
+	if cur := registered.Load(); cur != nil {
+		if cl, ok := cur.Classify(err); ok {
+			return classified(err, cl)
+		}
+	}
+	if cl, ok := builtinClassifier.Classify(err); ok {
+		return classified(err, cl)
+	}
+	return &Error{Code: code.Internal, Message: InternalMessage, Err: err}
+}
+
+// classified builds the *Error for a successful classification.
+func classified(err error, cl apis.Classification) *Error {
+	c := cl.Code
+	if c == code.Empty {
+		c = code.Internal
+	}
+	msg := cl.Message
+	if msg == "" {
+		msg = err.Error()
+	}
+	return &Error{Code: c, Reason: cl.Reason, Message: msg, Err: err}
+}
+
+// builtinClassifier implements the rules documented on Classify.
+// Order matters: context.DeadlineExceeded also satisfies net.Error.
+var builtinClassifier = apis.ClassifierFunc(func(err error) (apis.Classification, bool) {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return apis.Classification{Code: code.Canceled, Reason: ReasonContextCanceled, Message: "canceled"}, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return apis.Classification{Code: code.Timeout, Reason: ReasonContextDeadlineExceeded, Message: "deadline exceeded"}, true
+	case errors.Is(err, fs.ErrNotExist):
+		return apis.Classification{Code: code.NotFound, Reason: ReasonFSNotExist, Message: "not found"}, true
+	case errors.Is(err, fs.ErrExist):
+		return apis.Classification{Code: code.AlreadyExists, Reason: ReasonFSExist, Message: "already exists"}, true
+	case errors.Is(err, fs.ErrPermission):
+		return apis.Classification{Code: code.PermissionDenied, Reason: ReasonFSPermission, Message: "permission denied"}, true
+	case errors.Is(err, sql.ErrNoRows):
+		return apis.Classification{Code: code.NotFound, Reason: ReasonSQLNoRows, Message: "not found"}, true
+	case errors.Is(err, sql.ErrConnDone):
+		return apis.Classification{Code: code.Unavailable, Reason: ReasonSQLConnDone, Message: "database unavailable"}, true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		if ne.Timeout() {
+			return apis.Classification{Code: code.Timeout, Reason: ReasonNetworkTimeout, Message: "network timeout"}, true
+		}
+		return apis.Classification{Code: code.Unavailable, Reason: ReasonNetworkError, Message: "network error"}, true
+	}
+	return apis.Classification{}, false
+})
@@ -118,11 +118,17 @@ var Empty Code = ""
 
 // Parse takes a user-provided string, normalizes it and validates it.
 // On success it returns a canonical Code value.
+//
+// In strict mode (see SetStrict) well-formed but unregistered codes fail
+// with ErrCodeUnregistered.
 func Parse(s string) (Code, error) {
 	s = Normalize(s)
 	if err := validate(s); err != nil {
 		return Empty, err
 	}
+	if err := CheckRegistered(Code(s)); err != nil {
+		return Empty, err
+	}
 	return Code(s), nil
 }
 
@@ -178,7 +184,8 @@ func (c Code) MarshalText() ([]byte, error) {
 
 // UnmarshalText implements encoding.TextUnmarshaler.
 //
-// It normalizes and validates the provided text before assigning.
+// It normalizes and validates the provided text before assigning, with the
+// same strict-mode rules as Parse.
 func (c *Code) UnmarshalText(text []byte) error {
 	// We copy into a buffer to avoid changing the input slice.
 	s := string(bytes.TrimSpace(text))
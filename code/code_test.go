@@ -210,3 +210,108 @@ func TestRegexAndLengthAreConsistent(t *testing.T) {
 		t.Fatalf("expected %q (len=%d) to be invalid", longer, len(longer))
 	}
 }
+
+func TestInfo(t *testing.T) {
+	if m := Info(Unavailable); m.Category != CategoryTransient || !m.Retryable || !m.ServerFault {
+		t.Fatalf("Info(unavailable) = %+v", m)
+	}
+	if m := Info(Invalid); m.Category != CategoryClient || m.Retryable || m.ServerFault {
+		t.Fatalf("Info(invalid) = %+v", m)
+	}
+	if Info(Internal).Severity <= Info(Unavailable).Severity || Info(Unavailable).Severity <= Info(Invalid).Severity {
+		t.Fatal("severity must rank internal > unavailable > invalid")
+	}
+	if _, ok := Lookup("no_such_code"); ok {
+		t.Fatal("Lookup must report unknown codes")
+	}
+	if Info("no_such_code") != Info(Internal) {
+		t.Fatal("unknown codes must be treated like internal")
+	}
+}
+
+func TestInfo_CoversBuiltins(t *testing.T) {
+	for _, c := range []Code{
+		Internal, Invalid, Missing, Unsupported,
+		Unavailable, Timeout, Canceled, DependencyFailed, NotReady, Draining, Overloaded, Throttled,
+		NotFound, AlreadyExists, Conflict, PreconditionFailed, Gone, StaleVersion, DeprecationRejected,
+		Unauthenticated, InvalidCredentials, PermissionDenied, TokenInvalid, TokenExpired, TokenRevoked, SessionExpired,
+		Expired, TooEarly, RateLimited, QuotaExceeded,
+	} {
+		if _, ok := Lookup(c); !ok {
+			t.Errorf("missing metadata for %q", c)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	m := Meta{Category: CategoryResource, Retryable: true, Severity: SeverityWarning}
+	if err := Register("test_locked", m); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if got := Info("test_locked"); got != m {
+		t.Fatalf("Info = %+v, want %+v", got, m)
+	}
+	if err := Register("test_locked", m); err != ErrCodeRegistered {
+		t.Fatalf("second Register = %v, want ErrCodeRegistered", err)
+	}
+	if err := Register(NotFound, m); err != ErrCodeRegistered {
+		t.Fatalf("Register(builtin) = %v, want ErrCodeRegistered", err)
+	}
+	if err := Register("X", m); err != ErrCodeInvalid {
+		t.Fatalf("Register(invalid) = %v, want ErrCodeInvalid", err)
+	}
+}
+
+func TestStrictMode(t *testing.T) {
+	if _, err := Parse("not_fund"); err != nil {
+		t.Fatalf("non-strict Parse must accept well-formed codes: %v", err)
+	}
+
+	var seen []Code
+	SetUnregisteredHook(func(c Code) { seen = append(seen, c) })
+	SetStrict(true)
+	defer func() {
+		SetStrict(false)
+		SetUnregisteredHook(nil)
+	}()
+
+	if _, err := Parse("not_fund"); err != ErrCodeUnregistered {
+		t.Fatalf("strict Parse = %v, want ErrCodeUnregistered", err)
+	}
+	var c Code
+	if err := c.UnmarshalText([]byte("not_fund")); err != ErrCodeUnregistered {
+		t.Fatalf("strict UnmarshalText = %v, want ErrCodeUnregistered", err)
+	}
+	if got, err := Parse("Not-Found"); err != nil || got != NotFound {
+		t.Fatalf("strict Parse(builtin) = %q, %v", got, err)
+	}
+	if len(seen) != 2 || seen[0] != "not_fund" {
+		t.Fatalf("hook calls = %v", seen)
+	}
+
+	SetStrict(false)
+	if err := CheckRegistered("not_fund"); err != nil || len(seen) != 3 {
+		t.Fatalf("hook-only mode: err=%v calls=%d", err, len(seen))
+	}
+}
+
+func TestAllAndFreeze(t *testing.T) {
+	var n int
+	prev := Code("")
+	for c := range All() {
+		if c <= prev {
+			t.Fatalf("All not sorted: %q after %q", c, prev)
+		}
+		prev = c
+		n++
+	}
+	if n < 30 || !IsRegistered(Internal) {
+		t.Fatalf("All yielded %d codes", n)
+	}
+
+	Freeze()
+	defer frozen.Store(false)
+	if err := Register("test_after_freeze", Meta{}); err != ErrRegistryFrozen {
+		t.Fatalf("Register after Freeze = %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package code
+
+import (
+	"errors"
+	"maps"
+	"sync"
+	"sync/atomic"
+)
+
+// Category groups codes by the kind of failure they describe.
+type Category uint8
+
+const (
+	// CategoryServer is for unexpected failures inside the service itself.
+	CategoryServer Category = iota
+	// CategoryClient is for malformed, unsupported or ill-timed requests.
+	CategoryClient
+	// CategoryTransient is for temporary operational conditions that are
+	// expected to clear on their own.
+	CategoryTransient
+	// CategoryAuth is for authentication and authorization failures.
+	CategoryAuth
+	// CategoryResource is for resource existence, state and concurrency
+	// conflicts.
+	CategoryResource
+	// CategoryQuota is for rate limits, throttling and quotas.
+	CategoryQuota
+)
+
+// String returns the lower-case name of the category, e.g. "transient".
+func (c Category) String() string {
+	switch c {
+	case CategoryServer:
+		return "server"
+	case CategoryClient:
+		return "client"
+	case CategoryTransient:
+		return "transient"
+	case CategoryAuth:
+		return "auth"
+	case CategoryResource:
+		return "resource"
+	case CategoryQuota:
+		return "quota"
+	default:
+		return "unknown"
+	}
+}
+
+// Severity ranks codes by how much attention they deserve from operators.
+// Higher values are more severe, so severities can be compared directly.
+type Severity uint8
+
+const (
+	// SeverityInfo is for expected outcomes that need no attention,
+	// such as a caller canceling its own request.
+	SeverityInfo Severity = iota
+	// SeverityWarning is for failures caused by the caller.
+	SeverityWarning
+	// SeverityError is for failures of the service or its dependencies.
+	SeverityError
+	// SeverityCritical is for unexpected internal failures.
+	SeverityCritical
+)
+
+// String returns the lower-case name of the severity, e.g. "warning".
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// Meta describes the semantics of a code, so retry helpers, metrics,
+// redaction and mappers can make decisions without hardcoding lists of codes.
+type Meta struct {
+	// Category groups the code with similar failures.
+	Category Category
+
+	// Retryable reports whether repeating the same request may succeed
+	// without changes, possibly after a delay.
+	Retryable bool
+
+	// Severity ranks the code for alerting and for aggregation policies.
+	Severity Severity
+
+	// ServerFault reports whether the failure is attributable to the server
+	// (true) or to the caller (false).
+	ServerFault bool
+}
+
+var (
+	// ErrCodeRegistered is returned by Register when metadata for the code
+	// is already known.
+	ErrCodeRegistered = errors.New("derrors: code already registered")
+
+	// ErrRegistryFrozen is returned by Register after Freeze.
+	ErrRegistryFrozen = errors.New("derrors: code registry is frozen")
+)
+
+var (
+	// metas holds the metadata of every known code. It is replaced as a
+	// whole on Register, so readers only pay for an atomic load.
+	metas atomic.Pointer[map[Code]Meta]
+	// metasMu serializes writers.
+	metasMu sync.Mutex
+)
+
+func init() {
+	m := map[Code]Meta{
+		Internal:    {Category: CategoryServer, Severity: SeverityCritical, ServerFault: true},
+		Invalid:     {Category: CategoryClient, Severity: SeverityWarning},
+		Missing:     {Category: CategoryClient, Severity: SeverityWarning},
+		Unsupported: {Category: CategoryClient, Severity: SeverityWarning},
+
+		Unavailable:      {Category: CategoryTransient, Retryable: true, Severity: SeverityError, ServerFault: true},
+		Timeout:          {Category: CategoryTransient, Retryable: true, Severity: SeverityError, ServerFault: true},
+		Canceled:         {Category: CategoryClient, Severity: SeverityInfo},
+		DependencyFailed: {Category: CategoryServer, Severity: SeverityError, ServerFault: true},
+		NotReady:         {Category: CategoryTransient, Retryable: true, Severity: SeverityError, ServerFault: true},
+		Draining:         {Category: CategoryTransient, Retryable: true, Severity: SeverityError, ServerFault: true},
+		Overloaded:       {Category: CategoryTransient, Retryable: true, Severity: SeverityError, ServerFault: true},
+		Throttled:        {Category: CategoryQuota, Retryable: true, Severity: SeverityWarning},
+
+		NotFound:            {Category: CategoryResource, Severity: SeverityWarning},
+		AlreadyExists:       {Category: CategoryResource, Severity: SeverityWarning},
+		Conflict:            {Category: CategoryResource, Severity: SeverityWarning},
+		PreconditionFailed:  {Category: CategoryResource, Severity: SeverityWarning},
+		Gone:                {Category: CategoryResource, Severity: SeverityWarning},
+		StaleVersion:        {Category: CategoryResource, Severity: SeverityWarning},
+		DeprecationRejected: {Category: CategoryClient, Severity: SeverityWarning},
+
+		Unauthenticated:    {Category: CategoryAuth, Severity: SeverityWarning},
+		InvalidCredentials: {Category: CategoryAuth, Severity: SeverityWarning},
+		PermissionDenied:   {Category: CategoryAuth, Severity: SeverityWarning},
+		TokenInvalid:       {Category: CategoryAuth, Severity: SeverityWarning},
+		TokenExpired:       {Category: CategoryAuth, Severity: SeverityWarning},
+		TokenRevoked:       {Category: CategoryAuth, Severity: SeverityWarning},
+		SessionExpired:     {Category: CategoryAuth, Severity: SeverityWarning},
+
+		Expired:       {Category: CategoryClient, Severity: SeverityWarning},
+		TooEarly:      {Category: CategoryClient, Retryable: true, Severity: SeverityWarning},
+		RateLimited:   {Category: CategoryQuota, Retryable: true, Severity: SeverityWarning},
+		QuotaExceeded: {Category: CategoryQuota, Severity: SeverityWarning},
+	}
+	metas.Store(&m)
+}
+
+// Info returns the metadata of c. Codes without metadata, including the
+// empty code, are treated like Internal: an unclassified failure is assumed
+// to be the server's.
+func Info(c Code) Meta {
+	if m, ok := Lookup(c); ok {
+		return m
+	}
+	return (*metas.Load())[Internal]
+}
+
+// Lookup returns the metadata of c and whether c is known.
+func Lookup(c Code) (Meta, bool) {
+	m, ok := (*metas.Load())[c]
+	return m, ok
+}
+
+// Register adds metadata for a custom code, making it a registered code.
+//
+// It fails with ErrCodeInvalid when c is not canonical, with
+// ErrCodeRegistered when c already has metadata (built-in codes included),
+// and with ErrRegistryFrozen after Freeze. Register is meant to be called
+// during program start-up.
+func Register(c Code, m Meta) error {
+	if err := validate(string(c)); err != nil {
+		return err
+	}
+	metasMu.Lock()
+	defer metasMu.Unlock()
+	if frozen.Load() {
+		return ErrRegistryFrozen
+	}
+	cur := *metas.Load()
+	if _, ok := cur[c]; ok {
+		return ErrCodeRegistered
+	}
+	next := maps.Clone(cur)
+	next[c] = m
+	metas.Store(&next)
+	return nil
+}
+
+// MustRegister is the panic-on-error variant of Register, for package-level
+// var blocks and init functions.
+func MustRegister(c Code, m Meta) Code {
+	if err := Register(c, m); err != nil {
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,118 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package code
+
+import (
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"sync/atomic"
+)
+
+// ErrCodeUnregistered is returned by Parse, UnmarshalText and
+// CheckRegistered in strict mode when a code is well-formed but has not been
+// registered (see Register).
+var ErrCodeUnregistered = errors.New("derrors: unregistered code")
+
+// registryPolicy is what happens when an unregistered code is seen.
+type registryPolicy struct {
+	strict bool
+	hook   func(Code)
+}
+
+var (
+	// policy is nil unless strict mode or a hook is configured, so the
+	// common case costs a single atomic load.
+	policy atomic.Pointer[registryPolicy]
+	// frozen rejects further Register calls once set.
+	frozen atomic.Bool
+)
+
+// SetStrict turns strict mode on or off. In strict mode Parse,
+// UnmarshalText and CheckRegistered reject well-formed codes that are not
+// registered, which catches typos like "not_fund" at the boundary instead of
+// letting them fall through to the mapper's fallback.
+//
+// Built-in codes are always registered; custom codes must be registered with
+// Register before they are parsed.
+func SetStrict(on bool) {
+	updatePolicy(func(p *registryPolicy) { p.strict = on })
+}
+
+// SetUnregisteredHook installs fn to be called with every unregistered code
+// seen by Parse, UnmarshalText and CheckRegistered, in strict mode or not.
+// It is typically used to log or count unknown codes before turning strict
+// mode on. A nil fn removes the hook. fn must be safe for concurrent use.
+func SetUnregisteredHook(fn func(Code)) {
+	updatePolicy(func(p *registryPolicy) { p.hook = fn })
+}
+
+// CheckRegistered reports whether c may be used under the current policy.
+// It calls the hook for an unregistered c and returns ErrCodeUnregistered
+// when strict mode is on. Without strict mode and hook it always returns nil
+// and does not look c up.
+func CheckRegistered(c Code) error {
+	p := policy.Load()
+	if p == nil {
+		return nil
+	}
+	if _, ok := (*metas.Load())[c]; ok {
+		return nil
+	}
+	if p.hook != nil {
+		p.hook(c)
+	}
+	if p.strict {
+		return ErrCodeUnregistered
+	}
+	return nil
+}
+
+// IsRegistered reports whether c is a built-in or registered code.
+func IsRegistered(c Code) bool {
+	_, ok := Lookup(c)
+	return ok
+}
+
+// All returns the registered codes, built-ins included, in lexical order.
+// The sequence is a snapshot taken when All is called.
+func All() iter.Seq[Code] {
+	return slices.Values(slices.Sorted(maps.Keys(*metas.Load())))
+}
+
+// Freeze makes the registry read-only: later Register calls fail with
+// ErrRegistryFrozen. Call it once all custom codes are registered, e.g. at
+// the end of main's initialization.
+func Freeze() { frozen.Store(true) }
+
+// updatePolicy applies fn to a copy of the current policy and publishes it,
+// or clears it when it no longer does anything.
+func updatePolicy(fn func(*registryPolicy)) {
+	metasMu.Lock()
+	defer metasMu.Unlock()
+	var next registryPolicy
+	if cur := policy.Load(); cur != nil {
+		next = *cur
+	}
+	fn(&next)
+	if !next.strict && next.hook == nil {
+		policy.Store(nil)
+		return
+	}
+	policy.Store(&next)
+}
@@ -0,0 +1,168 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"fmt"
+	"sort"
+
+	"dirpx.dev/derrors/v2/apis"
+)
+
+// Ensure *Error satisfies the public contracts from derrors/apis, so adapters
+// can treat it exactly like any third-party error type.
+var (
+	_ apis.CodedError    = (*Error)(nil)
+	_ apis.ReasonedError = (*Error)(nil)
+	_ apis.DetailedError = (*Error)(nil)
+	_ apis.CausedError   = (*Error)(nil)
+	_ apis.ViewProvider  = (*Error)(nil)
+)
+
+// InternalMessage is the public message of errors that carry no code of
+// their own. Their text is kept server-side only, since it tends to leak
+// internals (driver errors, file paths, ...).
+const InternalMessage = "internal error"
+
+// DetailTypeExtra is the apis.Detail.Type used for plain Details entries that
+// are not already apis.Detail values.
+const DetailTypeExtra = "extra"
+
+// ErrorCode implements apis.CodedError.
+func (e *Error) ErrorCode() string {
+	if e == nil {
+		return ""
+	}
+	return string(e.Code)
+}
+
+// ErrorReason implements apis.ReasonedError.
+func (e *Error) ErrorReason() string {
+	if e == nil {
+		return ""
+	}
+	return string(e.Reason)
+}
+
+// ErrorDetails implements apis.DetailedError by projecting Details into a
+// slice of apis.Detail, ordered by key.
+//
+// Projection rules per entry:
+//
+//   - an apis.Detail value is used as-is (Field defaults to the map key);
+//   - a []apis.Detail value is flattened in its own order;
+//   - anything else becomes {Type: "extra", Field: key, Info: {"value": ...}}
+//     with the value rendered as a string.
+//
+// The returned slice is freshly allocated on every call.
+func (e *Error) ErrorDetails() []apis.Detail {
+	return e.projectDetails(false)
+}
+
+// PublicErrorDetails is ErrorDetails without the entries that must not reach
+// API clients: entries stored under a key declared with KeySensitive, and
+// projected details whose Field names such a key. Checking both means a
+// detail cannot bypass the filter by carrying a Field that differs from the
+// key it is stored under.
+func (e *Error) PublicErrorDetails() []apis.Detail {
+	return e.projectDetails(true)
+}
+
+// projectDetails implements ErrorDetails, optionally dropping sensitive
+// entries.
+func (e *Error) projectDetails(public bool) []apis.Detail {
+	if e == nil || len(e.Details) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(e.Details))
+	for k := range e.Details {
+		if public && KeyVisibility(k) == VisibilitySensitive {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]apis.Detail, 0, len(keys))
+	for _, k := range keys {
+		switch v := e.Details[k].(type) {
+		case apis.Detail:
+			if v.Field == "" {
+				v.Field = k
+			}
+			out = append(out, v)
+		case []apis.Detail:
+			out = append(out, v...)
+		default:
+			out = append(out, apis.Detail{
+				Type:  DetailTypeExtra,
+				Field: k,
+				Info:  map[string]string{"value": detailString(v)},
+			})
+		}
+	}
+	if public {
+		return PublicDetails(out)
+	}
+	return out
+}
+
+// ErrorView implements apis.ViewProvider.
+//
+// The view carries Code, Reason, Message and PublicErrorDetails. It does not include the
+// cause: causes are technical and stay out of public views.
+func (e *Error) ErrorView() apis.ErrorView {
+	if e == nil {
+		return apis.ErrorView{}
+	}
+	return apis.ErrorView{
+		Code:    string(e.Code),
+		Reason:  string(e.Reason),
+		Message: e.Message,
+		Details: e.PublicErrorDetails(),
+	}
+}
+
+// PublicDetails returns ds without the entries whose Field names a key
+// declared with KeySensitive. The input slice is not modified.
+//
+// Use (*Error).PublicErrorDetails for an *Error: it also checks the keys the
+// details are stored under, which are lost once details are projected.
+func PublicDetails(ds []apis.Detail) []apis.Detail {
+	var out []apis.Detail
+	for _, d := range ds {
+		if KeyVisibility(d.Field) == VisibilitySensitive {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
+// detailString renders a detail value as a string suitable for apis.Detail.Info.
+func detailString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case fmt.Stringer:
+		return x.String()
+	case error:
+		return x.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -19,8 +19,8 @@ package derrors
 import (
 	"fmt"
 
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 )
 
 // Error is the canonical rich error type for dirpx.
@@ -30,7 +30,7 @@ import (
 //   - Reason: optional, more specific machine-friendly cause;
 //   - Message: human-oriented description (what went wrong);
 //   - Details: arbitrary key/value payload (for logging / HTTP body);
-//   - Cause: wrapped underlying error for debugging / unwrapping.
+//   - Err: wrapped underlying error for debugging / unwrapping.
 //
 // All mutation helpers (WithX) return a shallow copy, so Error instances
 // can be safely shared and modified in a functional style.
@@ -53,9 +53,14 @@ type Error struct {
 	// The map is treated as immutable: WithDetail/WithDetails always copy it.
 	Details map[string]any
 
-	// Cause holds the wrapped underlying error (if any). This is used for
-	// errors.Is / errors.As and for debugging in lower layers.
-	Cause error
+	// Err holds the wrapped underlying error (if any). This is used for
+	// errors.Is / errors.As and for debugging in lower layers. It is also
+	// returned by Cause, which is why the field is not named Cause itself.
+	Err error
+
+	// stack holds raw program counters captured at construction time when
+	// stack capture is enabled (see EnableStacks and WithStackOption).
+	stack []uintptr
 }
 
 // E is a convenience constructor for Error.
@@ -68,11 +73,46 @@ type Error struct {
 //	)
 //
 // It always returns a *new* Error and applies all provided options in order.
+// When stack capture is enabled via EnableStacks, the caller's stack is recorded.
+//
+// In strict mode (see code.SetStrict) an unregistered code is replaced by
+// code.Internal and the original is kept under UnregisteredCodeKey, so a
+// typo surfaces as an internal error instead of being mapped by accident.
 func E(c code.Code, msg string, opts ...Option) *Error {
+	return newError(1, c, msg, opts)
+}
+
+// ESkip is E for helpers that build errors on behalf of their caller, such as
+// constructors over a catalog of error kinds. The captured stack (see
+// EnableStacks and WithStackOption) leaves out skip frames above the caller of
+// ESkip, so a helper passes 1 to make the stack start at its own caller.
+// ESkip(0, ...) is equivalent to E.
+func ESkip(skip int, c code.Code, msg string, opts ...Option) *Error {
+	return newError(skip+1, c, msg, opts)
+}
+
+// newError implements E and ESkip. skip is the number of frames between
+// newError and the frame the captured stack starts at: 1 for E itself.
+func newError(skip int, c code.Code, msg string, opts []Option) *Error {
 	e := &Error{Code: c, Message: msg}
+	if code.CheckRegistered(c) != nil {
+		e.Code = code.Internal
+		e.Details = map[string]any{UnregisteredCodeKey.Name(): c}
+	}
+	if d := stackDepth.Load(); d > 0 {
+		// 0: runtime.Callers, 1: captureStack, 2: newError, then the skip
+		// frames of E or ESkip and the helpers above it, then the caller.
+		e.stack = captureStack(3+skip, int(d))
+	}
+	captured := e.stack != nil
 	for _, opt := range opts {
 		e = opt(e)
 	}
+	if !captured && e.stack != nil {
+		// An option (WithStackOption) captured the stack relative to its
+		// own caller; capture it again from the right frame.
+		e.stack = captureStack(3+skip, forcedStackDepth())
+	}
 	return e
 }
 
@@ -98,7 +138,15 @@ func (e *Error) Error() string {
 }
 
 // Unwrap returns the underlying cause, enabling errors.Is / errors.As chains.
-func (e *Error) Unwrap() error { return e.Cause }
+func (e *Error) Unwrap() error { return e.Err }
+
+// Cause implements apis.CausedError. It returns the same error as Unwrap.
+func (e *Error) Cause() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
 
 // WithReason returns a shallow copy of e with the given Reason set.
 // The original error is not modified.
@@ -170,11 +218,20 @@ func (e *Error) WithDetails(kv map[string]any) *Error {
 
 // WithCause returns a shallow copy of e with the given underlying cause attached.
 // If err is nil, the original error is returned unchanged.
+//
+// When stack capture is enabled and e has no stack yet (e.g. it was built as a
+// struct literal), the stack of the WithCause caller is recorded.
 func (e *Error) WithCause(err error) *Error {
 	if err == nil {
 		return e
 	}
 	cp := *e
-	cp.Cause = err
+	cp.Err = err
+	if cp.stack == nil {
+		if d := stackDepth.Load(); d > 0 {
+			// 0: runtime.Callers, 1: captureStack, 2: WithCause, 3: caller.
+			cp.stack = captureStack(3, int(d))
+		}
+	}
 	return &cp
 }
@@ -17,12 +17,18 @@
 package derrors
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io/fs"
+	"net"
 	"strings"
 	"testing"
 
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 )
 
 func mustReason(t *testing.T, s string) reason.Reason {
@@ -80,6 +86,10 @@ func TestError_WithCause_Unwrap(t *testing.T) {
 	if errors.Unwrap(e) != root {
 		t.Fatal("Unwrap failed")
 	}
+	var ce apis.CausedError = e
+	if ce.Cause() != root {
+		t.Fatal("Cause failed")
+	}
 }
 
 func TestError_WithDetails_Merge(t *testing.T) {
@@ -92,3 +102,398 @@ func TestError_WithDetails_Merge(t *testing.T) {
 		t.Fatal("merge failed")
 	}
 }
+
+func TestError_Is_CodeAndReasonPrefix(t *testing.T) {
+	e := E(code.Unavailable, "db is down",
+		WithReasonOption(mustReason(t, "storage.pg.connect_timeout")),
+	)
+
+	if !errors.Is(e, &Error{Code: code.Unavailable}) {
+		t.Fatal("code-only target must match")
+	}
+	if errors.Is(e, &Error{Code: code.NotFound}) {
+		t.Fatal("different code must not match")
+	}
+	if !errors.Is(e, &Error{Reason: mustReason(t, "storage.pg")}) {
+		t.Fatal("reason prefix must match")
+	}
+	if !errors.Is(e, &Error{Code: code.Unavailable, Reason: mustReason(t, "storage.pg.connect_timeout")}) {
+		t.Fatal("full reason must match")
+	}
+	if errors.Is(e, &Error{Reason: mustReason(t, "storage.p")}) {
+		t.Fatal("prefix must respect segment boundaries")
+	}
+	if errors.Is(e, &Error{Code: code.Internal, Reason: mustReason(t, "storage.pg")}) {
+		t.Fatal("code and reason must both match")
+	}
+	if errors.Is(e, &Error{}) {
+		t.Fatal("empty target must not match")
+	}
+}
+
+func TestHasCode_HasReasonPrefix_WalkChain(t *testing.T) {
+	inner := E(code.NotFound, "no user", WithReasonOption(mustReason(t, "auth.jwt.subject")))
+	wrapped := fmt.Errorf("lookup: %w", inner)
+	joined := errors.Join(errors.New("other"), wrapped)
+
+	if !HasCode(joined, code.NotFound) {
+		t.Fatal("HasCode must find code inside errors.Join tree")
+	}
+	if HasCode(joined, code.Internal) {
+		t.Fatal("HasCode must not report absent code")
+	}
+	if !HasReasonPrefix(joined, "auth.jwt") {
+		t.Fatal("HasReasonPrefix must find reason inside errors.Join tree")
+	}
+	if HasReasonPrefix(joined, "auth.saml") {
+		t.Fatal("HasReasonPrefix must not report absent prefix")
+	}
+	if HasCode(nil, code.NotFound) || HasReasonPrefix(nil, "auth") {
+		t.Fatal("nil error must not match")
+	}
+}
+
+func TestError_Contracts_DetailsSorted(t *testing.T) {
+	e := E(code.Invalid, "bad input",
+		WithReasonOption(mustReason(t, "schema.group")),
+		WithDetailsOption(map[string]any{
+			"zeta":  1,
+			"alpha": "a",
+			"field": apis.Detail{Type: "field", Reason: "required"},
+		}),
+	)
+
+	var de apis.DetailedError = e
+	ds := de.ErrorDetails()
+	if len(ds) != 3 {
+		t.Fatalf("got %d details, want 3", len(ds))
+	}
+	wantFields := []string{"alpha", "field", "zeta"}
+	for i, d := range ds {
+		if d.Field != wantFields[i] {
+			t.Fatalf("details[%d].Field = %q, want %q", i, d.Field, wantFields[i])
+		}
+	}
+	if ds[0].Type != DetailTypeExtra || ds[0].Info["value"] != "a" {
+		t.Fatalf("plain value projected wrongly: %+v", ds[0])
+	}
+	if ds[1].Type != "field" || ds[1].Reason != "required" {
+		t.Fatalf("apis.Detail must be kept as-is: %+v", ds[1])
+	}
+	if ds[2].Info["value"] != "1" {
+		t.Fatalf("non-string value must be rendered: %+v", ds[2])
+	}
+
+	v := e.ErrorView()
+	if v.Code != "invalid" || v.Reason != "schema.group" || v.Message != "bad input" || len(v.Details) != 3 {
+		t.Fatalf("unexpected view: %+v", v)
+	}
+	if e.ErrorCode() != "invalid" || e.ErrorReason() != "schema.group" {
+		t.Fatal("code/reason accessors mismatch")
+	}
+}
+
+func TestStacks_DisabledByDefault(t *testing.T) {
+	e := E(code.Internal, "boom")
+	if e.StackTrace() != nil {
+		t.Fatal("stack must not be captured by default")
+	}
+	if got := fmt.Sprintf("%+v", e); strings.Contains(got, "\n\t") {
+		t.Fatalf("%%+v without stack must not print frames:\n%s", got)
+	}
+}
+
+func TestStacks_EnableStacks(t *testing.T) {
+	EnableStacks(8)
+	defer EnableStacks(0)
+
+	e := E(code.Internal, "boom")
+	frames := e.StackTrace()
+	if len(frames) == 0 {
+		t.Fatal("stack must be captured when enabled")
+	}
+	if !strings.HasSuffix(frames[0].Function, "TestStacks_EnableStacks") {
+		t.Fatalf("first frame = %q, want the test function", frames[0].Function)
+	}
+	if len(frames) > 8 {
+		t.Fatalf("captured %d frames, want at most 8", len(frames))
+	}
+	if out := fmt.Sprintf("%+v", e); !strings.Contains(out, "TestStacks_EnableStacks") {
+		t.Fatalf("%%+v must print frames:\n%s", out)
+	}
+
+	lit := (&Error{Code: code.Internal, Message: "lit"}).WithCause(errors.New("root"))
+	if f := lit.StackTrace(); len(f) == 0 || !strings.HasSuffix(f[0].Function, "TestStacks_EnableStacks") {
+		t.Fatal("WithCause must capture the caller's stack when none exists")
+	}
+}
+
+func TestStacks_WithStackOption(t *testing.T) {
+	e := E(code.Internal, "boom", WithStackOption())
+	frames := e.StackTrace()
+	if len(frames) == 0 || !strings.HasSuffix(frames[0].Function, "TestStacks_WithStackOption") {
+		t.Fatalf("WithStackOption must capture the E caller, got %+v", frames)
+	}
+}
+
+func TestError_Format(t *testing.T) {
+	root := errors.New("i/o timeout")
+	mid := fmt.Errorf("dial tcp: %w", root)
+	e := E(code.Unavailable, "db is down",
+		WithReasonOption(mustReason(t, "storage.pg.connect_timeout")),
+		WithDetailsOption(map[string]any{"retries": 3, "host": "db:5432"}),
+		WithCauseOption(mid),
+	)
+
+	if got := fmt.Sprintf("%v", e); got != e.Error() {
+		t.Fatalf("%%v = %q, want %q", got, e.Error())
+	}
+	if got := fmt.Sprintf("%s", e); got != e.Error() {
+		t.Fatalf("%%s = %q, want %q", got, e.Error())
+	}
+	if got, want := fmt.Sprintf("%q", e), `unavailable:storage.pg.connect_timeout: "db is down"`; got != want {
+		t.Fatalf("%%q = %q, want %q", got, want)
+	}
+
+	want := `code=unavailable reason=storage.pg.connect_timeout message="db is down"
+details: host="db:5432" retries=3
+cause: dial tcp: i/o timeout
+cause: i/o timeout`
+	if got := fmt.Sprintf("%+v", e); got != want {
+		t.Fatalf("%%+v mismatch\n--- want ---\n%s\n--- got ---\n%s", want, got)
+	}
+
+	var nilErr *Error
+	if got := fmt.Sprintf("%+v", nilErr); got != "<nil>" {
+		t.Fatalf("nil %%+v = %q", got)
+	}
+}
+
+func TestClassify_Builtins(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   code.Code
+		wantReason reason.Reason
+		wantMsg    string
+	}{
+		{"canceled", fmt.Errorf("op: %w", context.Canceled), code.Canceled, ReasonContextCanceled, "canceled"},
+		{"deadline", context.DeadlineExceeded, code.Timeout, ReasonContextDeadlineExceeded, "deadline exceeded"},
+		{"not exist", &fs.PathError{Op: "open", Path: "/etc/secret", Err: fs.ErrNotExist}, code.NotFound, ReasonFSNotExist, "not found"},
+		{"no rows", fmt.Errorf("get user: %w", sql.ErrNoRows), code.NotFound, ReasonSQLNoRows, "not found"},
+		{"net timeout", &net.DNSError{Err: "timeout", Name: "db.internal", IsTimeout: true}, code.Timeout, ReasonNetworkTimeout, "network timeout"},
+		{"net error", &net.DNSError{Err: "no such host", Name: "db.internal"}, code.Unavailable, ReasonNetworkError, "network error"},
+		{"unknown", errors.New("boom"), code.Internal, reason.Empty, InternalMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Classify(tt.err)
+			if e.Code != tt.wantCode || e.Reason != tt.wantReason {
+				t.Fatalf("Classify = %s/%s, want %s/%s", e.Code, e.Reason, tt.wantCode, tt.wantReason)
+			}
+			if e.Err != tt.err {
+				t.Fatal("original error must be kept as Err")
+			}
+			// The original text (paths, hosts) stays in Err only.
+			if e.Message != tt.wantMsg {
+				t.Fatalf("Message = %q, want %q", e.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestClassify_PassThroughAndRegistered(t *testing.T) {
+	if Classify(nil) != nil {
+		t.Fatal("nil must classify to nil")
+	}
+	de := E(code.Conflict, "x")
+	if Classify(fmt.Errorf("wrap: %w", de)) != de {
+		t.Fatal("existing *Error must be returned as-is")
+	}
+
+	// Restore the global chain so later tests see only the built-in rules.
+	prev := registered.Load()
+	t.Cleanup(func() { registered.Store(prev) })
+
+	sentinel := errors.New("quota store full")
+	RegisterClassifier(apis.ClassifierFunc(func(err error) (apis.Classification, bool) {
+		if errors.Is(err, sentinel) {
+			return apis.Classification{Code: code.QuotaExceeded, Message: "quota exceeded"}, true
+		}
+		return apis.Classification{}, false
+	}))
+	e := Classify(sentinel)
+	if e.Code != code.QuotaExceeded || e.Message != "quota exceeded" || e.Err != sentinel {
+		t.Fatalf("registered classifier not applied: %+v", e)
+	}
+
+	extra := apis.ClassifierFunc(func(error) (apis.Classification, bool) {
+		return apis.Classification{Code: code.Unavailable}, true
+	})
+	if got := ClassifyWith(context.Canceled, extra); got.Code != code.Unavailable {
+		t.Fatalf("extra classifiers must run first, got %s", got.Code)
+	}
+}
+
+func TestJoin_PoliciesAndMatching(t *testing.T) {
+	bad := E(code.Invalid, "name is empty", WithDetailOption("field", "name"))
+	down := E(code.Unavailable, "db is down")
+	root := errors.New("root")
+
+	j := Join(bad, nil, fmt.Errorf("save: %w", down), root)
+	if j.Code != code.Internal {
+		// root classifies to internal, which is the most severe.
+		t.Fatalf("most severe = %s, want internal", j.Code)
+	}
+	if !errors.Is(j, root) || !HasCode(j, code.Invalid) || !HasCode(j, code.Unavailable) {
+		t.Fatal("errors.Is must see every child")
+	}
+	// Plain children are matched by the code they were classified with.
+	first := JoinPolicy(PolicyFirst, bad, root)
+	if !HasCode(first, code.Internal) || !errors.Is(first, E(code.Internal, "")) {
+		t.Fatal("code matching must see the classified children")
+	}
+	var m *Multi
+	if !errors.As(j, &m) || m.Len() != 3 {
+		t.Fatalf("Join must wrap a *Multi with 3 children, got %v", m)
+	}
+	if j.Message != "3 errors occurred" {
+		t.Fatalf("Message = %q", j.Message)
+	}
+
+	if got := JoinPolicy(PolicyFirst, bad, down).Code; got != code.Invalid {
+		t.Fatalf("PolicyFirst = %s, want invalid", got)
+	}
+	if got := JoinPolicy(PolicyMajority, down, bad, bad).Code; got != code.Invalid {
+		t.Fatalf("PolicyMajority = %s, want invalid", got)
+	}
+	if got := Join(bad, down).Code; got != code.Unavailable {
+		t.Fatalf("PolicyMostSevere = %s, want unavailable", got)
+	}
+
+	// Among caller mistakes the category breaks the tie, whatever the order.
+	ranked := []code.Code{code.Invalid, code.NotFound, code.RateLimited, code.PermissionDenied, code.Timeout, code.Internal}
+	for i := 1; i < len(ranked); i++ {
+		lo, hi := E(ranked[i-1], "lo"), E(ranked[i], "hi")
+		if got := Join(hi, lo).Code; got != ranked[i] {
+			t.Errorf("Join(%s, %s) = %s, want %s", ranked[i], ranked[i-1], got, ranked[i])
+		}
+		if got := Join(lo, hi).Code; got != ranked[i] {
+			t.Errorf("Join(%s, %s) = %s, want %s", ranked[i-1], ranked[i], got, ranked[i])
+		}
+	}
+}
+
+func TestJoin_EdgeCases(t *testing.T) {
+	if Join() != nil || Join(nil, nil) != nil {
+		t.Fatal("Join without errors must return nil")
+	}
+	single := E(code.NotFound, "x")
+	if Join(nil, single) != single {
+		t.Fatal("Join with one error must return it unwrapped")
+	}
+	r := mustReason(t, "batch.item")
+	j := Join(E(code.Invalid, "a").WithReason(r), E(code.Missing, "b").WithReason(r))
+	if j.Reason != r {
+		t.Fatalf("common reason must be kept, got %q", j.Reason)
+	}
+}
+
+func TestKey_TypedAccessAndVisibility(t *testing.T) {
+	resourceID := NewKey[string]("test_resource_id", KeyPublic())
+	limit := NewKey[int]("test_limit")
+	token := NewKey[string]("test_token", KeySensitive())
+
+	e := E(code.QuotaExceeded, "quota exceeded",
+		WithValueOption(resourceID, "db-42"),
+		WithValueOption(token, "s3cr3t"),
+	)
+	e2 := WithValue(e, limit, 10)
+
+	if id, ok := Value(e2, resourceID); !ok || id != "db-42" {
+		t.Fatalf("Value(resourceID) = %q, %v", id, ok)
+	}
+	if n, ok := Value(e2, limit); !ok || n != 10 {
+		t.Fatalf("Value(limit) = %d, %v", n, ok)
+	}
+	if _, ok := Value(e, limit); ok {
+		t.Fatal("WithValue must not mutate the original error")
+	}
+	if _, ok := Value(E(code.Invalid, "x").WithDetail("test_limit", "ten"), limit); ok {
+		t.Fatal("Value must report false on type mismatch")
+	}
+
+	if KeyVisibility("test_token") != VisibilitySensitive || KeyVisibility("unknown_key") != VisibilityDefault {
+		t.Fatal("KeyVisibility mismatch")
+	}
+	for _, d := range e2.ErrorView().Details {
+		if d.Field == "test_token" {
+			t.Fatal("sensitive key must not appear in ErrorView")
+		}
+	}
+	if len(e2.ErrorDetails()) != 3 {
+		t.Fatal("ErrorDetails must keep all entries")
+	}
+
+	// A detail is filtered whether the sensitive name is its map key or its
+	// Field.
+	aliased := E(code.Invalid, "x").
+		WithDetail(token.Name(), apis.Detail{Type: "extra", Field: "alias"}).
+		WithDetail("alias", apis.Detail{Type: "extra", Field: token.Name()})
+	if ds := aliased.PublicErrorDetails(); len(ds) != 0 {
+		t.Fatalf("sensitive details leaked through a mismatched Field: %+v", ds)
+	}
+	if len(aliased.ErrorView().Details) != 0 || len(aliased.ErrorDetails()) != 2 {
+		t.Fatal("only public projections must drop aliased sensitive details")
+	}
+}
+
+func TestKey_RedeclareConflictPanics(t *testing.T) {
+	_ = NewKey[string]("test_conflict", KeyPublic())
+	_ = NewKey[string]("test_conflict", KeyPublic()) // same metadata is fine
+	defer func() {
+		if recover() == nil {
+			t.Fatal("redeclaring with different visibility must panic")
+		}
+	}()
+	_ = NewKey[string]("test_conflict", KeySensitive())
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{E(code.Unavailable, "down"), true},
+		{fmt.Errorf("wrap: %w", E(code.RateLimited, "slow down")), true},
+		{E(code.Invalid, "bad"), false},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+		{errors.New("opaque"), false},
+	}
+	for _, tc := range cases {
+		if got := IsRetryable(tc.err); got != tc.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestE_StrictDowngradesUnregistered(t *testing.T) {
+	code.SetStrict(true)
+	defer code.SetStrict(false)
+
+	e := E("not_fund", "typo")
+	if e.Code != code.Internal {
+		t.Fatalf("Code = %s, want internal", e.Code)
+	}
+	if orig, ok := Value(e, UnregisteredCodeKey); !ok || orig != "not_fund" {
+		t.Fatalf("UnregisteredCodeKey = %q, %v", orig, ok)
+	}
+	if ds := e.PublicErrorDetails(); len(ds) != 0 {
+		t.Fatalf("unregistered code must not be public: %+v", ds)
+	}
+	if e := E(code.NotFound, "ok"); e.Code != code.NotFound || e.Details != nil {
+		t.Fatalf("registered code must pass through: %+v", e)
+	}
+}
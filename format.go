@@ -0,0 +1,103 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Ensure *Error controls its own fmt output.
+var _ fmt.Formatter = (*Error)(nil)
+
+// Format implements fmt.Formatter.
+//
+// Supported verbs:
+//
+//   - %s, %v: the same text as Error();
+//   - %q:     like Error(), but with the message double-quoted,
+//     e.g. `not_found:user.lookup: "no such user"`;
+//   - %+v:    a multi-line debug dump: code, reason and message, then the
+//     details in sorted key order, then one line per error in the cause
+//     chain, then the captured stack (if any).
+//
+// Example %+v output:
+//
+//	code=unavailable reason=storage.pg.connect_timeout message="db is down"
+//	details: host="db:5432" retries=3
+//	cause: dial tcp 10.0.0.1:5432: i/o timeout
+//
+// Other verbs are reported the same way fmt reports bad verbs.
+func (e *Error) Format(s fmt.State, verb rune) {
+	if e == nil {
+		_, _ = io.WriteString(s, "<nil>")
+		return
+	}
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			e.formatVerbose(s)
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's':
+		_, _ = io.WriteString(s, e.Error())
+	case 'q':
+		if e.Reason != "" {
+			_, _ = fmt.Fprintf(s, "%s:%s: %q", e.Code, e.Reason, e.Message)
+			return
+		}
+		_, _ = fmt.Fprintf(s, "%s: %q", e.Code, e.Message)
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*derrors.Error=%s)", verb, e.Error())
+	}
+}
+
+// formatVerbose writes the %+v representation of e.
+func (e *Error) formatVerbose(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "code=%s", e.Code)
+	if e.Reason != "" {
+		_, _ = fmt.Fprintf(w, " reason=%s", e.Reason)
+	}
+	_, _ = fmt.Fprintf(w, " message=%q", e.Message)
+
+	if len(e.Details) > 0 {
+		keys := make([]string, 0, len(e.Details))
+		for k := range e.Details {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		_, _ = io.WriteString(w, "\ndetails:")
+		for _, k := range keys {
+			if v, ok := e.Details[k].(string); ok {
+				_, _ = fmt.Fprintf(w, " %s=%q", k, v)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, " %s=%v", k, e.Details[k])
+		}
+	}
+
+	// One line per link of the single-unwrap chain. Multi-error nodes
+	// (errors.Join) are printed once as a whole and end the walk.
+	for c := e.Err; c != nil; c = errors.Unwrap(c) {
+		_, _ = fmt.Fprintf(w, "\ncause: %s", c.Error())
+	}
+
+	writeStack(w, e.StackTrace())
+}
@@ -1,4 +1,4 @@
-module dirpx.dev/derrors
+module dirpx.dev/derrors/v2
 
 go 1.25.3
 
@@ -0,0 +1,103 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package grpcx
+
+import (
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+	gcodes "google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+)
+
+// statusCodes maps gRPC status codes returned by downstream calls onto
+// derrors codes. codes.OK is intentionally absent.
+//
+// A downstream rejecting our request for its input, preconditions or
+// credentials is not the fault of our caller: those statuses become
+// dependency_failed rather than invalid or unauthenticated.
+var statusCodes = map[gcodes.Code]code.Code{
+	gcodes.Canceled:           code.Canceled,
+	gcodes.Unknown:            code.Internal,
+	gcodes.InvalidArgument:    code.DependencyFailed,
+	gcodes.DeadlineExceeded:   code.Timeout,
+	gcodes.NotFound:           code.NotFound,
+	gcodes.AlreadyExists:      code.AlreadyExists,
+	gcodes.PermissionDenied:   code.DependencyFailed,
+	gcodes.ResourceExhausted:  code.RateLimited,
+	gcodes.FailedPrecondition: code.DependencyFailed,
+	gcodes.Aborted:            code.Conflict,
+	gcodes.OutOfRange:         code.DependencyFailed,
+	gcodes.Unimplemented:      code.Unsupported,
+	gcodes.Internal:           code.Internal,
+	gcodes.Unavailable:        code.Unavailable,
+	gcodes.DataLoss:           code.Internal,
+	gcodes.Unauthenticated:    code.DependencyFailed,
+}
+
+// rejectedMessage replaces the status message of statuses classified as
+// code.DependencyFailed; the downstream message describes the downstream
+// request, not the caller's.
+const rejectedMessage = "dependency rejected the request"
+
+// statusReasons holds the reason attached to each classified gRPC status,
+// "grpc.<canonical_name>", e.g. "grpc.deadline_exceeded".
+var statusReasons = map[gcodes.Code]reason.Reason{
+	gcodes.Canceled:           reason.MustParse("grpc.cancelled"),
+	gcodes.Unknown:            reason.MustParse("grpc.unknown"),
+	gcodes.InvalidArgument:    reason.MustParse("grpc.invalid_argument"),
+	gcodes.DeadlineExceeded:   reason.MustParse("grpc.deadline_exceeded"),
+	gcodes.NotFound:           reason.MustParse("grpc.not_found"),
+	gcodes.AlreadyExists:      reason.MustParse("grpc.already_exists"),
+	gcodes.PermissionDenied:   reason.MustParse("grpc.permission_denied"),
+	gcodes.ResourceExhausted:  reason.MustParse("grpc.resource_exhausted"),
+	gcodes.FailedPrecondition: reason.MustParse("grpc.failed_precondition"),
+	gcodes.Aborted:            reason.MustParse("grpc.aborted"),
+	gcodes.OutOfRange:         reason.MustParse("grpc.out_of_range"),
+	gcodes.Unimplemented:      reason.MustParse("grpc.unimplemented"),
+	gcodes.Internal:           reason.MustParse("grpc.internal"),
+	gcodes.Unavailable:        reason.MustParse("grpc.unavailable"),
+	gcodes.DataLoss:           reason.MustParse("grpc.data_loss"),
+	gcodes.Unauthenticated:    reason.MustParse("grpc.unauthenticated"),
+}
+
+// StatusClassifier classifies gRPC status errors (anything understood by
+// status.FromError) returned by downstream calls.
+//
+// The derrors code follows the canonical gRPC semantics (e.g. NotFound ->
+// not_found, DeadlineExceeded -> timeout), except that caller-fault statuses
+// (InvalidArgument, FailedPrecondition, OutOfRange, Unauthenticated,
+// PermissionDenied) become dependency_failed with a fixed message. The reason
+// is "grpc.<name>" and the message is otherwise the status message.
+//
+// UnaryServerInterceptor applies it automatically; register it with
+// derrors.RegisterClassifier to make derrors.Classify aware of gRPC as well.
+var StatusClassifier apis.Classifier = apis.ClassifierFunc(func(err error) (apis.Classification, bool) {
+	st, ok := gstatus.FromError(err)
+	if !ok {
+		return apis.Classification{}, false
+	}
+	c, ok := statusCodes[st.Code()]
+	if !ok {
+		return apis.Classification{}, false
+	}
+	msg := st.Message()
+	if c == code.DependencyFailed {
+		msg = rejectedMessage
+	}
+	return apis.Classification{Code: c, Reason: statusReasons[st.Code()], Message: msg}, true
+})
@@ -19,14 +19,14 @@ package grpcx
 import (
 	"context"
 
-	"dirpx.dev/derrors/apis"
+	"dirpx.dev/derrors/v2/apis"
 	"google.golang.org/grpc"
 	gcodes "google.golang.org/grpc/codes"
 	gstatus "google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/anypb"
 
-	"dirpx.dev/derrors"
-	derrorsv1 "dirpx.dev/derrors/api/derrors/v1"
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/adapter"
+	derrorsv1 "dirpx.dev/derrors/v2/api/derrors/v1"
 )
 
 // Extras holds optional, rich metadata that can be embedded into
@@ -67,19 +67,49 @@ type Extras struct {
 // It can return an empty Extras if nothing is available.
 type MetaFn func(ctx context.Context, e *derrors.Error) Extras
 
+// Option configures UnaryServerInterceptor.
+type Option func(*config)
+
+type config struct {
+	redactor apis.Redactor
+}
+
+// WithRedactor applies r to the status message, violations and causes before
+// they leave the server (see package redact for the public and internal
+// profiles). Extras.Causes are dropped as well when r does not expose causes.
+func WithRedactor(r apis.Redactor) Option {
+	return func(c *config) { c.redactor = r }
+}
+
 // UnaryServerInterceptor returns a gRPC UnaryServerInterceptor that
 // maps derrors.Error into gRPC errors with rich derrors.v1.ErrorDescriptor details.
 //
+// Handler errors that are not *derrors.Error are converted with
+// derrors.ClassifyWith(err, StatusClassifier), so context cancellations,
+// downstream gRPC statuses and other well-known errors get proper codes.
+// Unrecognized errors are reported as code.Internal with
+// derrors.InternalMessage; their text never reaches the status message.
+//
 // The provided apis.Mapper is used to map domain error codes/reasons
 // into transport status codes.
 //
 // The optional MetaFn can be used to extract additional metadata from context
 // and the domain error to populate the ErrorDescriptor. If nil, no extra metadata
 // will be added.
-func UnaryServerInterceptor(m apis.Mapper, metaFn MetaFn) grpc.UnaryServerInterceptor {
+//
+// Without WithRedactor, messages and Extras are exposed as-is; the children's
+// causes of an aggregate are only exposed by a redactor that allows them.
+func UnaryServerInterceptor(m apis.Mapper, metaFn MetaFn, opts ...Option) grpc.UnaryServerInterceptor {
 	if metaFn == nil {
 		metaFn = func(context.Context, *derrors.Error) Extras { return Extras{} }
 	}
+	var cfg config
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	redact := adapter.WithRedactor(cfg.redactor)
 
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		resp, err := handler(ctx, req)
@@ -87,20 +117,28 @@ func UnaryServerInterceptor(m apis.Mapper, metaFn MetaFn) grpc.UnaryServerInterc
 			return resp, nil
 		}
 
-		de, ok := err.(*derrors.Error)
-		if !ok {
-			// Not ours — return as-is.
-			return nil, err
-		}
+		// Errors that are not ours (context errors, downstream gRPC statuses,
+		// driver errors, ...) are classified first, keeping the original as Err.
+		de := derrors.ClassifyWith(err, StatusClassifier)
 
 		st := m.Status(de.Code, de.Reason)
 		ex := metaFn(ctx, de)
 
+		// Aggregates (derrors.Join) expose every child as a violation and
+		// every child cause as a shallow cause entry.
+		violations := appendNew(ex.Violations, adapter.Violations(de, redact))
+		causes := ex.Causes
+		if cfg.redactor != nil && !cfg.redactor.ExposeCauses(de.Code) {
+			causes = nil
+		}
+		causes = appendNew(causes, adapter.Causes(de, redact))
+		msg := adapter.RedactMessage(cfg.redactor, de.Code, de.Reason, de.Message)
+
 		desc := &derrorsv1.ErrorDescriptor{
 			// Core identity.
 			Code:    string(de.Code),
 			Reason:  string(de.Reason),
-			Message: de.Message,
+			Message: msg,
 
 			// Transport projections.
 			HttpStatus: int32(st.HTTP),
@@ -114,28 +152,37 @@ func UnaryServerInterceptor(m apis.Mapper, metaFn MetaFn) grpc.UnaryServerInterc
 			// Client hints.
 			Retry:      ex.Retry,
 			Quota:      ex.Quota,
-			Violations: ex.Violations,
+			Violations: violations,
 
 			// Human-facing + diagnostics.
 			Links:  ex.Links,
-			Causes: ex.Causes,
+			Causes: causes,
 			Env:    ex.Env,
 			Tags:   ex.Tags,
 		}
 
-		base := gstatus.New(gcodes.Code(st.GRPC), de.Message)
+		base := gstatus.New(gcodes.Code(st.GRPC), msg)
 
 		// Try to attach descriptor as details. If it fails — return base.
-		if anyDesc, err := anypb.New(desc); err == nil {
-			if with, err := base.WithDetails(anyDesc); err == nil {
-				return nil, with.Err()
-			}
+		// WithDetails packs desc into an Any itself.
+		if with, err := base.WithDetails(desc); err == nil {
+			return nil, with.Err()
 		}
 
 		return nil, base.Err()
 	}
 }
 
+// appendNew returns a followed by b without mutating a's backing array.
+func appendNew[T any](a, b []T) []T {
+	if len(b) == 0 {
+		return a
+	}
+	out := make([]T, 0, len(a)+len(b))
+	out = append(out, a...)
+	return append(out, b...)
+}
+
 // ExtractDescriptor pulls derrors.v1.ErrorDescriptor out of a gRPC error, if present.
 // Useful in tests and client code.
 func ExtractDescriptor(err error) (*derrorsv1.ErrorDescriptor, bool) {
@@ -0,0 +1,176 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	gcodes "google.golang.org/grpc/codes"
+	gstatus "google.golang.org/grpc/status"
+
+	"dirpx.dev/derrors/v2"
+	derrorsv1 "dirpx.dev/derrors/v2/api/derrors/v1"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/mapper"
+	"dirpx.dev/derrors/v2/redact"
+)
+
+// call runs the interceptor around a handler that fails with err.
+func call(t *testing.T, err error, metaFn MetaFn, opts ...Option) (*gstatus.Status, *derrorsv1.ErrorDescriptor) {
+	t.Helper()
+	m, merr := mapper.New()
+	if merr != nil {
+		t.Fatal(merr)
+	}
+	icpt := UnaryServerInterceptor(m, metaFn, opts...)
+	_, got := icpt(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test.Svc/Do"},
+		func(context.Context, any) (any, error) { return nil, err })
+	st, ok := gstatus.FromError(got)
+	if !ok {
+		t.Fatalf("not a status error: %v", got)
+	}
+	desc, ok := ExtractDescriptor(got)
+	if !ok {
+		t.Fatalf("no descriptor in %v", got)
+	}
+	return st, desc
+}
+
+func joined() *derrors.Error {
+	name := derrors.E(code.Invalid, "name is required", derrors.WithValueOption(derrors.FieldKey, "name")).
+		WithCause(errors.New("empty string"))
+	age := derrors.E(code.Invalid, "age must be positive", derrors.WithValueOption(derrors.FieldKey, "age"))
+	return derrors.Join(name, age)
+}
+
+func TestInterceptor_Join(t *testing.T) {
+	ex := Extras{
+		Violations: make([]*derrorsv1.Violation, 1, 4),
+		Causes:     make([]*derrorsv1.Cause, 1, 4),
+	}
+	ex.Violations[0] = &derrorsv1.Violation{Field: "meta"}
+	ex.Causes[0] = &derrorsv1.Cause{Type: "meta"}
+	metaFn := func(context.Context, *derrors.Error) Extras { return ex }
+
+	st, desc := call(t, joined(), metaFn, WithRedactor(redact.Internal()))
+	if st.Code() != gcodes.InvalidArgument {
+		t.Fatalf("code = %v", st.Code())
+	}
+	if len(desc.Violations) != 3 || desc.Violations[1].Field != "name" || desc.Violations[2].Field != "age" {
+		t.Fatalf("violations = %v", desc.Violations)
+	}
+	if len(desc.Causes) != 2 || desc.Causes[1].Message != "empty string" {
+		t.Fatalf("causes = %v", desc.Causes)
+	}
+	// The children must not be appended into the spare capacity of the
+	// slices returned by MetaFn.
+	if ex.Violations[:2][1] != nil || ex.Causes[:2][1] != nil {
+		t.Fatal("Extras modified")
+	}
+}
+
+func TestInterceptor_CausesOptIn(t *testing.T) {
+	metaFn := func(context.Context, *derrors.Error) Extras {
+		return Extras{Causes: []*derrorsv1.Cause{{Type: "meta"}}}
+	}
+
+	// Without a redactor, Extras are exposed as-is but child causes are not.
+	_, desc := call(t, joined(), metaFn)
+	if len(desc.Causes) != 1 || desc.Causes[0].Type != "meta" {
+		t.Fatalf("causes = %v", desc.Causes)
+	}
+
+	_, desc = call(t, joined(), metaFn, WithRedactor(redact.Public()))
+	if len(desc.Causes) != 0 {
+		t.Fatalf("public causes = %v", desc.Causes)
+	}
+}
+
+func TestStatusClassifier(t *testing.T) {
+	tests := []struct {
+		st      gcodes.Code
+		want    code.Code
+		wantMsg string
+	}{
+		{gcodes.NotFound, code.NotFound, "downstream says"},
+		{gcodes.DeadlineExceeded, code.Timeout, "downstream says"},
+		{gcodes.InvalidArgument, code.DependencyFailed, rejectedMessage},
+		{gcodes.FailedPrecondition, code.DependencyFailed, rejectedMessage},
+		{gcodes.OutOfRange, code.DependencyFailed, rejectedMessage},
+		{gcodes.Unauthenticated, code.DependencyFailed, rejectedMessage},
+		{gcodes.PermissionDenied, code.DependencyFailed, rejectedMessage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.st.String(), func(t *testing.T) {
+			err := gstatus.Error(tt.st, "downstream says")
+			e := derrors.ClassifyWith(err, StatusClassifier)
+			if e.Code != tt.want || e.Message != tt.wantMsg || e.Err != err {
+				t.Fatalf("classified = %s %q, want %s %q", e.Code, e.Message, tt.want, tt.wantMsg)
+			}
+		})
+	}
+}
+
+var tokenKey = derrors.NewKey[string]("grpcx_test_token", derrors.KeySensitive())
+
+// serverFailure is a server-fault aggregate whose message, details and
+// causes carry internal data.
+func serverFailure() *derrors.Error {
+	db := derrors.E(code.Internal, "pq: password auth failed for 10.0.0.7").
+		WithCause(errors.New("dial tcp 10.0.0.7:5432"))
+	cache := derrors.E(code.Unavailable, "cache-1 is down")
+	e := derrors.Join(db, cache).WithDetail("host", "10.0.0.7")
+	return derrors.WithValue(e, tokenKey, "hunter2")
+}
+
+func TestInterceptor_Profiles(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		redactor   *redact.Policy
+		wantMsg    string
+		wantCauses int
+	}{
+		{"public", redact.Public(), redact.DefaultGenericMessage, 0},
+		{"internal", redact.Internal(), "2 errors occurred", 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			st, desc := call(t, serverFailure(), nil, WithRedactor(tt.redactor))
+			if st.Code() != gcodes.Internal || desc.GrpcCode != int32(gcodes.Internal) {
+				t.Fatalf("code = %v / %d", st.Code(), desc.GrpcCode)
+			}
+			if st.Message() != tt.wantMsg || desc.Message != tt.wantMsg {
+				t.Fatalf("messages = %q / %q", st.Message(), desc.Message)
+			}
+			if len(desc.Causes) != tt.wantCauses {
+				t.Fatalf("causes = %v", desc.Causes)
+			}
+			// Details are never part of the descriptor.
+			if s := desc.String(); strings.Contains(s, "hunter2") {
+				t.Fatalf("sensitive detail in descriptor: %s", s)
+			}
+			if tt.name == "public" {
+				if s := desc.String(); strings.Contains(s, "10.0.0.7") {
+					t.Fatalf("internal data in descriptor: %s", s)
+				}
+			}
+		})
+	}
+}
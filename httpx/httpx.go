@@ -20,9 +20,11 @@ import (
 	"net/http"
 	"strconv"
 
-	"dirpx.dev/derrors"
-	derrorsv1 "dirpx.dev/derrors/api/derrors/v1"
-	"dirpx.dev/derrors/apis"
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/adapter"
+	derrorsv1 "dirpx.dev/derrors/v2/api/derrors/v1"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/i18n"
 	"google.golang.org/protobuf/encoding/protojson"
 )
 
@@ -36,20 +38,42 @@ type Meta struct {
 	RetryAfterSeconds int32
 	Links             []*derrorsv1.Link
 	Fields            []*derrorsv1.Violation
+
+	// AcceptLanguage is the raw Accept-Language request header. It selects
+	// the locale when the Writer has a Localizer.
+	AcceptLanguage string
 }
 
 // Writer is a thin adapter that knows how to turn a derrors.Error into an HTTP
 // response using the provided status mapper.
 type Writer struct {
 	Mapper apis.Mapper
+
+	// Redactor, when set, is applied to the message and to aggregate
+	// violations before they are written (see package redact for the
+	// public and internal profiles). Aggregate causes are written only when
+	// it exposes them.
+	Redactor apis.Redactor
+
+	// Localizer, when set, replaces the message with the template for the
+	// best locale from Meta.AcceptLanguage (or the localizer's default
+	// locale), rendered with the error details that pass the Redactor (see
+	// package i18n).
+	Localizer apis.Localizer
 }
 
 // Write serializes a View that conforms to error.view.schema.json and writes it
 // to the response writer. The HTTP status is resolved via the Mapper.
 //
-// No automatic redaction or filtering is performed here: whatever is present
-// in the error and Meta is exposed as-is. Higher-level handlers should apply
-// policies if needed.
+// When err aggregates several errors (derrors.Join), each child is appended to
+// the "fields" violations after meta.Fields, and the children's causes are
+// written to "causes" when the Redactor exposes causes for err's code. Causes
+// are opt-in: a nil Redactor does not write them.
+//
+// The message is localized first and redacted afterwards, so a redaction
+// policy also applies to localized text. Redaction is performed only when
+// Writer.Redactor is set. Meta is always
+// exposed as-is: it is assembled by the caller for this very response.
 func (w Writer) Write(rw http.ResponseWriter, err *derrors.Error, meta Meta) {
 	if err == nil {
 		return
@@ -57,16 +81,38 @@ func (w Writer) Write(rw http.ResponseWriter, err *derrors.Error, meta Meta) {
 
 	st := w.Mapper.Status(err.Code, err.Reason)
 
+	// Aggregates (derrors.Join) expose every child as a field violation and
+	// every child cause as a shallow cause entry.
+	redact := adapter.WithRedactor(w.Redactor)
+	fields := meta.Fields
+	if vs := adapter.Violations(err, redact); len(vs) > 0 {
+		fields = make([]*derrorsv1.Violation, 0, len(meta.Fields)+len(vs))
+		fields = append(fields, meta.Fields...)
+		fields = append(fields, vs...)
+	}
+
+	msg := err.Message
+	if w.Localizer != nil {
+		locales := i18n.ParseAcceptLanguage(meta.AcceptLanguage)
+		if tmpl, ok := i18n.Negotiate(w.Localizer, err.Code, err.Reason, locales); ok {
+			// Parameters come from the details as redacted for this
+			// response, so dropped or masked values stay out of the text.
+			ds := adapter.RedactDetails(w.Redactor, err.Code, err.PublicErrorDetails())
+			msg = i18n.Render(tmpl, i18n.DetailParams(ds))
+		}
+	}
+
 	view := &derrorsv1.ErrorView{
 		Code:              string(err.Code),
-		Message:           err.Message,
+		Message:           adapter.RedactMessage(w.Redactor, err.Code, err.Reason, msg),
 		Reason:            string(err.Reason),
 		Correlation:       meta.Correlation,
 		TraceId:           meta.TraceID,
 		SpanId:            meta.SpanID,
 		RetryAfterSeconds: meta.RetryAfterSeconds,
 		Links:             meta.Links,
-		Fields:            meta.Fields,
+		Fields:            fields,
+		Causes:            adapter.Causes(err, redact),
 	}
 
 	rw.Header().Set("Content-Type", "application/json")
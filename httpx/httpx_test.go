@@ -0,0 +1,186 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package httpx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	derrorsv1 "dirpx.dev/derrors/v2/api/derrors/v1"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/i18n"
+	"dirpx.dev/derrors/v2/mapper"
+	"dirpx.dev/derrors/v2/redact"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func newWriter(t *testing.T) Writer {
+	t.Helper()
+	m, err := mapper.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Writer{Mapper: m}
+}
+
+func write(t *testing.T, w Writer, err *derrors.Error, meta Meta) (*httptest.ResponseRecorder, *derrorsv1.ErrorView) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	w.Write(rec, err, meta)
+	var v derrorsv1.ErrorView
+	if err := protojson.Unmarshal(rec.Body.Bytes(), &v); err != nil {
+		t.Fatalf("body %s: %v", rec.Body, err)
+	}
+	return rec, &v
+}
+
+func joined() *derrors.Error {
+	name := derrors.E(code.Invalid, "name is required", derrors.WithValueOption(derrors.FieldKey, "name")).
+		WithCause(errors.New("empty string"))
+	age := derrors.E(code.Invalid, "age must be positive", derrors.WithValueOption(derrors.FieldKey, "age"))
+	return derrors.Join(name, age)
+}
+
+func TestWrite_Join(t *testing.T) {
+	w := newWriter(t)
+	w.Redactor = redact.Internal()
+	own := &derrorsv1.Violation{Field: "meta", Reason: "router"}
+	meta := Meta{Fields: make([]*derrorsv1.Violation, 1, 4)}
+	meta.Fields[0] = own
+
+	rec, v := write(t, w, joined(), meta)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if len(v.Fields) != 3 || v.Fields[0].Field != "meta" || v.Fields[1].Field != "name" || v.Fields[2].Field != "age" {
+		t.Fatalf("fields = %v", v.Fields)
+	}
+	if len(v.Causes) != 1 || v.Causes[0].Message != "empty string" {
+		t.Fatalf("causes = %v", v.Causes)
+	}
+	// The children must not be appended into the spare capacity of the
+	// caller's slice.
+	if len(meta.Fields) != 1 || meta.Fields[:2][1] != nil {
+		t.Fatalf("meta.Fields modified: %v", meta.Fields[:2])
+	}
+}
+
+func TestWrite_CausesOptIn(t *testing.T) {
+	_, v := write(t, newWriter(t), joined(), Meta{})
+	if len(v.Fields) != 2 {
+		t.Fatalf("fields = %v", v.Fields)
+	}
+	if len(v.Causes) != 0 {
+		t.Fatalf("causes without a redactor = %v", v.Causes)
+	}
+}
+
+var tokenKey = derrors.NewKey[string]("httpx_test_token", derrors.KeySensitive())
+
+// serverFailure is a server-fault aggregate whose message, details and
+// causes carry internal data.
+func serverFailure() *derrors.Error {
+	db := derrors.E(code.Internal, "pq: password auth failed for 10.0.0.7").
+		WithCause(errors.New("dial tcp 10.0.0.7:5432"))
+	cache := derrors.E(code.Unavailable, "cache-1 is down")
+	e := derrors.Join(db, cache).WithDetail("host", "10.0.0.7")
+	return derrors.WithValue(e, tokenKey, "hunter2")
+}
+
+func TestWrite_Profiles(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		redactor   *redact.Policy
+		wantMsg    string
+		wantCauses int
+	}{
+		{"public", redact.Public(), redact.DefaultGenericMessage, 0},
+		{"internal", redact.Internal(), "2 errors occurred", 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newWriter(t)
+			w.Redactor = tt.redactor
+			rec, v := write(t, w, serverFailure(), Meta{})
+			if rec.Code != http.StatusInternalServerError {
+				t.Fatalf("status = %d", rec.Code)
+			}
+			if v.Message != tt.wantMsg {
+				t.Fatalf("message = %q", v.Message)
+			}
+			if len(v.Causes) != tt.wantCauses {
+				t.Fatalf("causes = %v", v.Causes)
+			}
+			// Details are never part of the view.
+			if body := rec.Body.String(); strings.Contains(body, "hunter2") {
+				t.Fatalf("sensitive detail in body: %s", body)
+			}
+			if tt.name == "public" {
+				if body := rec.Body.String(); strings.Contains(body, "10.0.0.7") {
+					t.Fatalf("internal data in body: %s", body)
+				}
+				for _, f := range v.Fields {
+					if f.Message != redact.DefaultGenericMessage {
+						t.Fatalf("child message = %q", f.Message)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWrite_Localized(t *testing.T) {
+	cat := i18n.NewCatalog("en")
+	cat.Add("en", code.QuotaExceeded, "", "quota for {resource} exceeded ({limit}, {owner})")
+	cat.Add("de", code.QuotaExceeded, "", "Kontingent für {resource} überschritten ({limit}, {owner})")
+	cat.Add("de", code.Internal, "", "Interner Fehler bei {resource}")
+
+	w := newWriter(t)
+	w.Localizer = cat
+	w.Redactor = redact.Public(redact.WithMaskedKeys("limit"), redact.WithDroppedKeys("owner"))
+
+	quota := derrors.E(code.QuotaExceeded, "quota exceeded",
+		derrors.WithDetailOption("resource", "cpu"),
+		derrors.WithDetailOption("limit", 8),
+		derrors.WithDetailOption("owner", "bob"),
+	)
+	for _, tt := range []struct {
+		name   string
+		header string
+		err    *derrors.Error
+		want   string
+	}{
+		{"negotiated", "fr;q=1, de;q=0.8", quota, "Kontingent für cpu überschritten (" + redact.DefaultMask + ", {owner})"},
+		{"default locale", "", quota, "quota for cpu exceeded (" + redact.DefaultMask + ", {owner})"},
+		// The localized text is redacted like any other message.
+		{"redacted after localizing", "de", derrors.E(code.Internal, "x", derrors.WithDetailOption("resource", "cpu")), redact.DefaultGenericMessage},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set("Accept-Language", tt.header)
+			}
+			_, v := write(t, w, tt.err, Meta{AcceptLanguage: req.Header.Get("Accept-Language")})
+			if v.Message != tt.want {
+				t.Fatalf("message = %q, want %q", v.Message, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language header
+// value ordered by preference (quality, then position). The wildcard "*"
+// and tags with q=0 are dropped; malformed quality values count as q=1.
+//
+//	ParseAcceptLanguage("de-CH, de;q=0.9, en;q=0.8, *;q=0.1") // [de-CH de en]
+func ParseAcceptLanguage(h string) []string {
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for part := range strings.SplitSeq(h, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.TrimSpace(name)
+		if name == "" || name == "*" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		tags = append(tags, tag{name, q})
+	}
+	slices.SortStableFunc(tags, func(a, b tag) int { return cmp.Compare(b.q, a.q) })
+	out := make([]string, len(tags))
+	for i, t := range tags {
+		out[i] = t.name
+	}
+	return out
+}
+
+// Negotiate resolves the template for (c, r) by trying each locale in order
+// and finally the localizer's default locale (the empty locale).
+func Negotiate(l apis.Localizer, c code.Code, r reason.Reason, locales []string) (string, bool) {
+	if l == nil {
+		return "", false
+	}
+	for _, loc := range locales {
+		if tmpl, ok := l.Localize(c, r, loc); ok {
+			return tmpl, true
+		}
+	}
+	return l.Localize(c, r, "")
+}
@@ -0,0 +1,203 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Catalog is an in-memory apis.Localizer. It is safe for concurrent use;
+// it is typically filled once at start-up and only read afterwards.
+type Catalog struct {
+	mu       sync.RWMutex
+	fallback string
+	msgs     map[string]map[string]string // locale -> key -> template
+}
+
+var _ apis.Localizer = (*Catalog)(nil)
+
+// NewCatalog returns an empty catalog whose default locale is
+// defaultLocale. The default locale is used when Localize is called with an
+// empty locale.
+func NewCatalog(defaultLocale string) *Catalog {
+	return &Catalog{
+		fallback: canonicalLocale(defaultLocale),
+		msgs:     map[string]map[string]string{},
+	}
+}
+
+// Add registers the template for (c, r) in locale, replacing any previous
+// one. An empty reason registers the code-level template.
+func (cat *Catalog) Add(locale string, c code.Code, r reason.Reason, tmpl string) {
+	locale = canonicalLocale(locale)
+	cat.mu.Lock()
+	defer cat.mu.Unlock()
+	m := cat.msgs[locale]
+	if m == nil {
+		m = map[string]string{}
+		cat.msgs[locale] = m
+	}
+	m[messageKey(c, r)] = tmpl
+}
+
+// AddJSON registers the templates of a JSON object for locale. Keys are
+// "code" or "code:reason"; values are templates:
+//
+//	{"not_found": "not found", "quota_exceeded:cpu": "quota {resource} exceeded ({limit})"}
+func (cat *Catalog) AddJSON(locale string, data []byte) error {
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("i18n: %s: %w", locale, err)
+	}
+	return cat.addAll(locale, m)
+}
+
+// LoadFS loads every "<locale>.json" and "<locale>.po" file in dir of fsys.
+// Other files are ignored.
+func (cat *Catalog) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("i18n: %w", err)
+	}
+	for _, ent := range entries {
+		if ent.IsDir() {
+			continue
+		}
+		name := ent.Name()
+		ext := path.Ext(name)
+		if ext != ".json" && ext != ".po" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, name))
+		if err != nil {
+			return fmt.Errorf("i18n: %w", err)
+		}
+		locale := strings.TrimSuffix(name, ext)
+		if ext == ".json" {
+			err = cat.AddJSON(locale, data)
+		} else {
+			err = cat.AddPO(locale, data)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Locales returns the locales that have at least one template, sorted.
+func (cat *Catalog) Locales() []string {
+	cat.mu.RLock()
+	defer cat.mu.RUnlock()
+	out := make([]string, 0, len(cat.msgs))
+	for l := range cat.msgs {
+		out = append(out, l)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Localize implements apis.Localizer.
+//
+// Locales are tried from the most specific ("pt-br") to the base language
+// ("pt"); an empty locale selects the default locale. Within a locale the
+// reason is shortened segment by segment down to the code-level template.
+// A more general template in the requested language wins over a more
+// specific one in another language.
+func (cat *Catalog) Localize(c code.Code, r reason.Reason, locale string) (string, bool) {
+	locale = canonicalLocale(locale)
+	if locale == "" {
+		locale = cat.fallback
+	}
+	cat.mu.RLock()
+	defer cat.mu.RUnlock()
+	for l := locale; l != ""; l = baseLocale(l) {
+		m := cat.msgs[l]
+		if m == nil {
+			continue
+		}
+		for rr := r; ; rr = rr.Parent() {
+			if tmpl, ok := m[messageKey(c, rr)]; ok {
+				return tmpl, true
+			}
+			if rr == reason.Empty {
+				break
+			}
+		}
+	}
+	return "", false
+}
+
+// addAll validates and registers keyed templates for locale.
+func (cat *Catalog) addAll(locale string, m map[string]string) error {
+	for k, tmpl := range m {
+		c, r, err := parseKey(k)
+		if err != nil {
+			return fmt.Errorf("i18n: %s: key %q: %w", locale, k, err)
+		}
+		cat.Add(locale, c, r, tmpl)
+	}
+	return nil
+}
+
+// parseKey splits and validates a "code" or "code:reason" key.
+func parseKey(k string) (code.Code, reason.Reason, error) {
+	cs, rs, _ := strings.Cut(k, ":")
+	c, err := code.Parse(cs)
+	if err != nil {
+		return code.Empty, reason.Empty, err
+	}
+	r, err := reason.Parse(rs)
+	if err != nil {
+		return code.Empty, reason.Empty, err
+	}
+	return c, r, nil
+}
+
+// messageKey is the lookup key for (c, r), in the same "code:reason" layout
+// as Error().
+func messageKey(c code.Code, r reason.Reason) string {
+	if r == reason.Empty {
+		return string(c)
+	}
+	return string(c) + ":" + string(r)
+}
+
+// canonicalLocale lower-cases a BCP 47 tag and uses "-" as separator, so
+// "pt_BR" and "pt-BR" are the same locale.
+func canonicalLocale(l string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(l), "_", "-"))
+}
+
+// baseLocale drops the last subtag of l, returning "" for a bare language.
+func baseLocale(l string) string {
+	i := strings.LastIndexByte(l, '-')
+	if i < 0 {
+		return ""
+	}
+	return l[:i]
+}
@@ -0,0 +1,42 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package i18n provides message templates and localized message catalogs
+// for derrors.
+//
+// Templates use named placeholders that take their values from the error
+// details:
+//
+//	i18n.Render("quota {resource} exceeded ({limit})", e.Details)
+//	// quota cpu exceeded (8)
+//
+// A Catalog implements apis.Localizer. It stores templates per locale and
+// per "code" or "code:reason" key, and resolves them with two fallbacks:
+//
+//   - locale: "pt-BR" falls back to "pt"; the empty locale selects the
+//     catalog's default locale;
+//   - reason: "not_found:user.profile.missing" falls back to
+//     "not_found:user.profile", ..., and finally to "not_found".
+//
+// Catalogs are filled in code with Add, or loaded from JSON objects or
+// PO-style files, typically embedded with embed.FS:
+//
+//	//go:embed messages
+//	var messages embed.FS
+//
+//	cat := i18n.NewCatalog("en")
+//	if err := cat.LoadFS(messages, "messages"); err != nil { ... } // en.json, de.po, ...
+package i18n
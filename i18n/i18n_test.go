@@ -0,0 +1,154 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+var tokenKey = derrors.NewKey[string]("i18n_test_token", derrors.KeySensitive())
+
+func TestRender(t *testing.T) {
+	params := map[string]any{"resource": "cpu", "limit": 8, "err": errors.New("boom")}
+	cases := []struct{ tmpl, want string }{
+		{"plain", "plain"},
+		{"quota {resource} exceeded ({limit})", "quota cpu exceeded (8)"},
+		{"{missing} stays", "{missing} stays"},
+		{"{{literal}} {err}", "{literal} boom"},
+		{"unterminated {resource", "unterminated {resource"},
+	}
+	for _, tc := range cases {
+		if got := Render(tc.tmpl, params); got != tc.want {
+			t.Errorf("Render(%q) = %q, want %q", tc.tmpl, got, tc.want)
+		}
+	}
+}
+
+func TestParams_SkipsSensitive(t *testing.T) {
+	e := derrors.E(code.Unauthenticated, "x",
+		derrors.WithDetailOption("user", "bob"),
+		derrors.WithValueOption(tokenKey, "secret"),
+	)
+	if got := Render("{user} {i18n_test_token}", Params(e)); got != "bob {i18n_test_token}" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestDetailParams_FollowRedaction(t *testing.T) {
+	e := derrors.E(code.QuotaExceeded, "x",
+		derrors.WithDetailOption("resource", "cpu"),
+		derrors.WithDetailOption("limit", 8),
+		derrors.WithDetailOption("owner", "bob"),
+	)
+	// Simulate a redactor that dropped "owner" and masked "limit".
+	var ds []apis.Detail
+	for _, d := range e.PublicErrorDetails() {
+		switch d.Field {
+		case "owner":
+			continue
+		case "limit":
+			d.Info = map[string]string{"value": "***"}
+		}
+		ds = append(ds, d)
+	}
+	got := Render("{resource} {limit} {owner}", DetailParams(ds))
+	if got != "cpu *** {owner}" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestCatalog_Fallbacks(t *testing.T) {
+	cat := NewCatalog("en")
+	cat.Add("en", code.NotFound, "", "not found")
+	cat.Add("en", code.NotFound, "user", "user not found")
+	cat.Add("pt", code.NotFound, "", "não encontrado")
+	cat.Add("pt-BR", code.NotFound, "user.profile", "perfil não encontrado")
+
+	cases := []struct {
+		reason, locale, want string
+	}{
+		{"user.profile.missing", "pt_BR", "perfil não encontrado"},
+		{"user.profile", "pt-PT", "não encontrado"},
+		{"user.profile", "", "user not found"},
+		{"", "en-US", "not found"},
+	}
+	for _, tc := range cases {
+		got, ok := cat.Localize(code.NotFound, reason.Reason(tc.reason), tc.locale)
+		if !ok || got != tc.want {
+			t.Errorf("Localize(%q, %q) = %q, %v; want %q", tc.reason, tc.locale, got, ok, tc.want)
+		}
+	}
+	if _, ok := cat.Localize(code.NotFound, "", "fr"); ok {
+		t.Error("unknown locale must not silently use the default")
+	}
+	if got, ok := Negotiate(cat, code.NotFound, "", []string{"fr", "de"}); !ok || got != "not found" {
+		t.Errorf("Negotiate = %q, %v", got, ok)
+	}
+}
+
+func TestCatalog_LoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"msg/en.json": {Data: []byte(`{"quota_exceeded": "quota {resource} exceeded ({limit})"}`)},
+		"msg/de.po": {Data: []byte(`# German
+msgid ""
+msgstr "Content-Type: text/plain; charset=UTF-8\n"
+
+msgid "quota_exceeded"
+msgstr "Kontingent {resource} "
+"überschritten ({limit})"
+`)},
+		"msg/README.md": {Data: []byte("ignored")},
+	}
+	cat := NewCatalog("en")
+	if err := cat.LoadFS(fsys, "msg"); err != nil {
+		t.Fatal(err)
+	}
+	if got := cat.Locales(); !slices.Equal(got, []string{"de", "en"}) {
+		t.Fatalf("Locales = %v", got)
+	}
+	got, _ := cat.Localize(code.QuotaExceeded, "", "de-AT")
+	if got != "Kontingent {resource} überschritten ({limit})" {
+		t.Fatalf("de template = %q", got)
+	}
+
+	if err := cat.AddJSON("en", []byte(`{"Bad Code!": "x"}`)); err == nil {
+		t.Fatal("invalid key must be rejected")
+	}
+	if err := cat.AddPO("en", []byte("msgid \"a\"\nmsgid_plural \"b\"\n")); err == nil {
+		t.Fatal("plural forms must be rejected")
+	}
+	err := cat.AddPO("en", []byte("# ctx\nmsgctxt \"menu\"\nmsgid \"not_found\"\nmsgstr \"x\"\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2: msgctxt is not supported") {
+		t.Fatalf("msgctxt must be rejected with its line, got %v", err)
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	got := ParseAcceptLanguage("en;q=0.8, de-CH, *;q=0.1, fr;q=0, de;q=0.9")
+	if want := []string{"de-CH", "de", "en"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
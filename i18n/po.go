@@ -0,0 +1,93 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// AddPO registers the templates of a PO-style file for locale.
+//
+// Only the subset needed for error messages is supported: "msgid" holds the
+// "code" or "code:reason" key, "msgstr" the template, and both may continue
+// on following quoted lines. Comments and the header entry (empty msgid) are
+// skipped; entries with an empty msgstr are treated as untranslated. Plural
+// forms and message contexts (msgctxt) are rejected with the offending line,
+// since keys already carry the code and reason.
+//
+//	# quota errors
+//	msgid "quota_exceeded:cpu"
+//	msgstr "Kontingent {resource} überschritten ({limit})"
+func (cat *Catalog) AddPO(locale string, data []byte) error {
+	m := map[string]string{}
+	var (
+		id, str string
+		cur     *string
+		inEntry bool
+	)
+	flush := func() {
+		if inEntry && id != "" && str != "" {
+			m[id] = str
+		}
+		id, str, cur, inEntry = "", "", nil, false
+	}
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		var (
+			rest string
+			ok   bool
+		)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.HasPrefix(line, "msgid_plural"), strings.HasPrefix(line, "msgstr["):
+			return fmt.Errorf("i18n: %s line %d: plural forms are not supported", locale, n)
+		case strings.HasPrefix(line, "msgctxt"):
+			return fmt.Errorf("i18n: %s line %d: msgctxt is not supported", locale, n)
+		case strings.HasPrefix(line, `"`):
+			if cur == nil {
+				continue
+			}
+			rest, ok = line, true
+		default:
+			if rest, ok = strings.CutPrefix(line, "msgid "); ok {
+				flush()
+				inEntry, cur = true, &id
+			} else if rest, ok = strings.CutPrefix(line, "msgstr "); ok {
+				cur = &str
+			} else {
+				return fmt.Errorf("i18n: %s line %d: unexpected %q", locale, n, line)
+			}
+		}
+		s, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return fmt.Errorf("i18n: %s line %d: %w", locale, n, err)
+		}
+		*cur += s
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("i18n: %s: %w", locale, err)
+	}
+	flush()
+	return cat.addAll(locale, m)
+}
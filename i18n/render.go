@@ -0,0 +1,117 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+)
+
+// Render replaces every "{name}" placeholder in tmpl with params[name].
+//
+// Placeholders without a matching parameter are left untouched, so a broken
+// template degrades to readable text instead of failing. Use "{{" and "}}"
+// for literal braces. Values are rendered with their String or Error method
+// when they have one, and with fmt.Sprint otherwise.
+func Render(tmpl string, params map[string]any) string {
+	if !strings.ContainsAny(tmpl, "{}") {
+		return tmpl
+	}
+	var b strings.Builder
+	b.Grow(len(tmpl))
+	for i := 0; i < len(tmpl); i++ {
+		ch := tmpl[i]
+		switch {
+		case (ch == '{' || ch == '}') && i+1 < len(tmpl) && tmpl[i+1] == ch:
+			b.WriteByte(ch)
+			i++
+		case ch == '{':
+			end := strings.IndexByte(tmpl[i+1:], '}')
+			if end < 0 {
+				b.WriteString(tmpl[i:])
+				return b.String()
+			}
+			name := tmpl[i+1 : i+1+end]
+			if v, ok := params[name]; ok {
+				b.WriteString(valueString(v))
+			} else {
+				b.WriteString(tmpl[i : i+end+2])
+			}
+			i += end + 1
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// Params returns the details of e that may be rendered into a message:
+// entries whose key was declared with derrors.KeySensitive are left out.
+//
+// Params does not apply any redaction policy, so it suits messages built on
+// the server side (see catalog.New). Messages rendered for a response should
+// use DetailParams on the details that survived redaction instead.
+func Params(e *derrors.Error) map[string]any {
+	if e == nil || len(e.Details) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(e.Details))
+	for k, v := range e.Details {
+		if derrors.KeyVisibility(k) == derrors.VisibilitySensitive {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// DetailParams returns template parameters from projected details, such as
+// the redacted details of a view: each detail carrying an Info "value" (the
+// projection of a plain Details entry, see (*derrors.Error).ErrorDetails)
+// yields a parameter named after its Field. Details dropped by a redactor are
+// therefore absent, and masked ones render as the mask.
+func DetailParams(ds []apis.Detail) map[string]any {
+	var out map[string]any
+	for _, d := range ds {
+		v, ok := d.Info["value"]
+		if !ok || d.Field == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(ds))
+		}
+		out[d.Field] = v
+	}
+	return out
+}
+
+// valueString renders a parameter value.
+func valueString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case fmt.Stringer:
+		return x.String()
+	case error:
+		return x.Error()
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"errors"
+
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Is reports whether e matches target for errors.Is.
+//
+// A target matches when it is a *Error whose non-empty fields agree with e:
+//
+//   - target.Code, when set, must equal e.Code;
+//   - target.Reason, when set, must be a segment prefix of e.Reason
+//     ("storage.pg" matches "storage.pg.connect_timeout" but not "storage.pgx").
+//
+// Message, Details and Err of the target are ignored. A target with neither
+// Code nor Reason only matches itself (pointer identity), so an empty
+// &Error{} never matches everything by accident.
+//
+// Usage:
+//
+//	if errors.Is(err, &derrors.Error{Code: code.NotFound}) { ... }
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok || e == nil || t == nil {
+		return false
+	}
+	if e == t {
+		return true
+	}
+	if t.Code == code.Empty && t.Reason == reason.Empty {
+		return false
+	}
+	if t.Code != code.Empty && t.Code != e.Code {
+		return false
+	}
+	if !e.Reason.HasPrefix(t.Reason) {
+		return false
+	}
+	return true
+}
+
+// HasCode reports whether any *Error in err's chain carries the code c.
+//
+// The whole chain is inspected, including multi-error trees built with
+// errors.Join or fmt.Errorf with several %w verbs.
+func HasCode(err error, c code.Code) bool {
+	if err == nil || c == code.Empty {
+		return false
+	}
+	return errors.Is(err, &Error{Code: c})
+}
+
+// HasReasonPrefix reports whether any *Error in err's chain has a Reason
+// that starts with the segment prefix p, e.g. "auth.jwt" matches
+// "auth.jwt.expired".
+//
+// Like HasCode, it walks the whole chain including errors.Join trees.
+func HasReasonPrefix(err error, p reason.Reason) bool {
+	if err == nil || p == reason.Empty {
+		return false
+	}
+	return errors.Is(err, &Error{Reason: p})
+}
+
+// IsRetryable reports whether repeating the operation that produced err may
+// succeed, according to code.Info of err's code.
+//
+// Errors that carry no *Error are classified first (see Classify), so
+// context.DeadlineExceeded or a net.Error timeout count as retryable while
+// context.Canceled does not. A nil error is not retryable.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return code.Info(Classify(err).Code).Retryable
+}
@@ -0,0 +1,162 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"fmt"
+	"sync"
+
+	"dirpx.dev/derrors/v2/code"
+)
+
+// Visibility tells adapters who may see the value stored under a detail key.
+type Visibility uint8
+
+const (
+	// VisibilityDefault means no explicit policy. Adapters treat such keys
+	// as public, which matches the behavior of plain string keys.
+	VisibilityDefault Visibility = iota
+
+	// VisibilityPublic marks values that are safe to expose to API clients.
+	VisibilityPublic
+
+	// VisibilitySensitive marks values that must stay internal (logs,
+	// traces). Adapters drop them from public views.
+	VisibilitySensitive
+)
+
+// String returns the lowercase name of the visibility.
+func (v Visibility) String() string {
+	switch v {
+	case VisibilityPublic:
+		return "public"
+	case VisibilitySensitive:
+		return "sensitive"
+	default:
+		return "default"
+	}
+}
+
+// Key is a typed handle for a Details entry. It gives compile-time checked
+// access on top of the untyped Details map:
+//
+//	var ResourceID = derrors.NewKey[string]("resource_id")
+//
+//	e = derrors.WithValue(e, ResourceID, "db-42")
+//	id, ok := derrors.Value(e, ResourceID) // id is a string
+//
+// Keys are declared once, usually as package-level variables.
+type Key[T any] struct {
+	name string
+	vis  Visibility
+}
+
+// KeyOption configures a Key at declaration time.
+type KeyOption func(*keyMeta)
+
+// keyMeta is the metadata recorded for a key name.
+type keyMeta struct {
+	vis Visibility
+}
+
+// KeyPublic marks the key as safe to expose to API clients.
+func KeyPublic() KeyOption {
+	return func(m *keyMeta) { m.vis = VisibilityPublic }
+}
+
+// KeySensitive marks the key as internal-only; adapters drop it from views.
+func KeySensitive() KeyOption {
+	return func(m *keyMeta) { m.vis = VisibilitySensitive }
+}
+
+// keyRegistry maps key names to their metadata so adapters can look up
+// the visibility of plain Details entries.
+var keyRegistry sync.Map // map[string]keyMeta
+
+// NewKey declares a typed key for the given Details name.
+//
+// The key's metadata is recorded globally under name. Declaring the same name
+// twice with different metadata panics, since adapters could not tell which
+// policy applies.
+func NewKey[T any](name string, opts ...KeyOption) Key[T] {
+	var m keyMeta
+	for _, opt := range opts {
+		opt(&m)
+	}
+	if prev, loaded := keyRegistry.LoadOrStore(name, m); loaded && prev.(keyMeta) != m {
+		panic(fmt.Sprintf("derrors: key %q redeclared with visibility %s (was %s)",
+			name, m.vis, prev.(keyMeta).vis))
+	}
+	return Key[T]{name: name, vis: m.vis}
+}
+
+// Name returns the Details key the Key reads and writes.
+func (k Key[T]) Name() string { return k.name }
+
+// Visibility returns the key's declared visibility.
+func (k Key[T]) Visibility() Visibility { return k.vis }
+
+// String implements fmt.Stringer.
+func (k Key[T]) String() string { return k.name }
+
+// KeyVisibility returns the visibility declared for a Details key name, or
+// VisibilityDefault when no Key was declared for it.
+func KeyVisibility(name string) Visibility {
+	if m, ok := keyRegistry.Load(name); ok {
+		return m.(keyMeta).vis
+	}
+	return VisibilityDefault
+}
+
+// WithValue returns a shallow copy of e with v stored under k.
+// It is the typed counterpart of (*Error).WithDetail and copies Details the
+// same way.
+func WithValue[T any](e *Error, k Key[T], v T) *Error {
+	return e.WithDetail(k.name, v)
+}
+
+// Value returns the value stored under k and whether it was present with the
+// expected type.
+func Value[T any](e *Error, k Key[T]) (T, bool) {
+	var zero T
+	if e == nil {
+		return zero, false
+	}
+	v, ok := e.Details[k.name].(T)
+	if !ok {
+		return zero, false
+	}
+	return v, true
+}
+
+// WithValueOption stores a typed value on construction.
+// Intended to be used with E(...).
+func WithValueOption[T any](k Key[T], v T) Option {
+	return func(e *Error) *Error {
+		return WithValue(e, k, v)
+	}
+}
+
+// FieldKey holds the path of the input field an error refers to, e.g.
+// "spec.replicas". Adapters use it to fill violation field paths.
+var FieldKey = NewKey[string]("field", KeyPublic())
+
+// UnregisteredCodeKey holds the original code of an error that E downgraded
+// to code.Internal because the code was not registered in strict mode (see
+// code.SetStrict). It is sensitive: the unregistered code is a server-side
+// bug and stays in logs, out of views.
+var UnregisteredCodeKey = NewKey[code.Code]("unregistered_code", KeySensitive())
@@ -0,0 +1,74 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// Ensure *Error renders as a structured group in log/slog.
+var _ slog.LogValuer = (*Error)(nil)
+
+// LogValue implements slog.LogValuer.
+//
+// The error is rendered as a group:
+//
+//	code, reason (if set), message,
+//	details (a sub-group in sorted key order, if any),
+//	cause   (a sub-group, if any).
+//
+// A *Error cause is rendered recursively with the same layout; any other cause
+// becomes a group with a single "message" attribute. Detail values are passed
+// to slog untouched, so they may be LogValuers themselves.
+func (e *Error) LogValue() slog.Value {
+	if e == nil {
+		return slog.StringValue("<nil>")
+	}
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs, slog.String("code", string(e.Code)))
+	if e.Reason != "" {
+		attrs = append(attrs, slog.String("reason", string(e.Reason)))
+	}
+	attrs = append(attrs, slog.String("message", e.Message))
+
+	if len(e.Details) > 0 {
+		keys := make([]string, 0, len(e.Details))
+		for k := range e.Details {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		ds := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			ds = append(ds, slog.Any(k, e.Details[k]))
+		}
+		attrs = append(attrs, slog.Attr{Key: "details", Value: slog.GroupValue(ds...)})
+	}
+
+	if e.Err != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: causeLogValue(e.Err)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// causeLogValue renders a cause as a nested group.
+func causeLogValue(err error) slog.Value {
+	if lv, ok := err.(slog.LogValuer); ok {
+		return lv.LogValue()
+	}
+	return slog.GroupValue(slog.String("message", err.Error()))
+}
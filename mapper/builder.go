@@ -19,7 +19,8 @@ package mapper
 import (
 	"net/http"
 
-	"dirpx.dev/derrors/code"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 	"google.golang.org/grpc/codes"
 )
 
@@ -46,15 +47,38 @@ type builder struct {
 	// grpcOverride holds exact per-code gRPC overrides as ints; converted in New().
 	grpcOverride map[code.Code]int
 
+	// httpExact and grpcExact hold per-code exact-reason rules; the rule's
+	// prefix field carries the full reason. They are validated and turned
+	// into hash maps in New().
+	httpExact map[code.Code][]prefixRule
+	grpcExact map[code.Code][]prefixRule
+
 	// httpPrefixes holds per-code LPM rules for HTTP, defined as raw prefixRule
 	// and later compiled into a segment trie.
 	httpPrefixes map[code.Code][]prefixRule
 	// grpcPrefixes holds per-code LPM rules for gRPC.
 	grpcPrefixes map[code.Code][]prefixRule
 
+	// httpReversePref and grpcReversePref order the candidates of ambiguous
+	// reverse lookups; they are consulted before defaultReversePreference.
+	httpReversePref []code.Code
+	grpcReversePref []code.Code
+
+	// precedence ranks overrides against per-reason rules.
+	precedence Precedence
+
+	// grammar validates prefix rules and matched reasons.
+	grammar reason.Grammar
+
 	// global fallbacks used when a code has no default at all.
 	fallbackHTTP int
 	fallbackGRPC codes.Code
+
+	// onFallback is called when a resolution ends in a fallback.
+	onFallback func(code.Code, reason.Reason)
+
+	// strict makes New fail on lint errors (see WithStrict).
+	strict bool
 }
 
 // newBuilder creates an empty builder with maps pre-sized
@@ -68,9 +92,13 @@ func newBuilder() *builder {
 		// overrides and prefixes are usually few
 		httpOverride: make(map[code.Code]int),
 		grpcOverride: make(map[code.Code]int),
+		httpExact:    make(map[code.Code][]prefixRule),
+		grpcExact:    make(map[code.Code][]prefixRule),
 		httpPrefixes: make(map[code.Code][]prefixRule),
 		grpcPrefixes: make(map[code.Code][]prefixRule),
 
+		grammar: reason.DefaultGrammar,
+
 		// hard fallbacks if the code was never seen
 		fallbackHTTP: http.StatusInternalServerError,
 		fallbackGRPC: codes.Internal,
@@ -0,0 +1,678 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+	"google.golang.org/grpc/codes"
+)
+
+// ConfigVersion is the document version read and written by this package.
+const ConfigVersion = 1
+
+// Config is the file form of the mapper rules. It is what FromConfig and
+// LoadFile read and what WriteConfig writes:
+//
+//	{
+//	  "version": 1,
+//	  "precedence": "prefix-first",
+//	  "fallback": {"http": 500, "grpc": "INTERNAL"},
+//	  "defaults": {"canceled": {"http": 499}},
+//	  "overrides": {"draining": {"http": 503, "grpc": "UNAVAILABLE"}},
+//	  "exact": [
+//	    {"code": "unauthenticated", "reason": "auth.jwt", "http": 401}
+//	  ],
+//	  "prefixes": [
+//	    {"code": "unavailable", "prefix": "storage.pg", "http": 503}
+//	  ]
+//	}
+//
+// Only rules on top of the library defaults are stored. Codes are written in
+// canonical form, gRPC statuses by their upper-case name ("NOT_FOUND").
+type Config struct {
+	// Version must be ConfigVersion.
+	Version int `json:"version"`
+
+	// Precedence is "prefix-first" (the default when empty) or
+	// "override-first"; see WithPrecedence.
+	Precedence string `json:"precedence,omitempty"`
+
+	// Grammar, when set, replaces reason.DefaultGrammar (see WithGrammar).
+	Grammar *GrammarConfig `json:"grammar,omitempty"`
+
+	// Fallback sets the statuses used for codes without any rule (see
+	// WithHTTPFallback).
+	Fallback *Rule `json:"fallback,omitempty"`
+
+	// Defaults and Overrides are keyed by code (see WithHTTPDefault and
+	// WithHTTPOverride).
+	Defaults  map[string]Rule `json:"defaults,omitempty"`
+	Overrides map[string]Rule `json:"overrides,omitempty"`
+
+	// Exact are the per-code exact-reason rules (see WithHTTPExact).
+	Exact []ExactRule `json:"exact,omitempty"`
+
+	// Prefixes are the per-code reason-prefix rules (see WithHTTPPrefix).
+	Prefixes []PrefixRule `json:"prefixes,omitempty"`
+
+	// Reverse holds the reverse-mapping preferences (see
+	// WithHTTPReversePreference).
+	Reverse *ReverseConfig `json:"reverse,omitempty"`
+}
+
+// Rule is a pair of transport statuses. A zero HTTP or an empty GRPC leaves
+// that transport untouched; at least one of them must be set.
+type Rule struct {
+	HTTP int    `json:"http,omitempty"`
+	GRPC string `json:"grpc,omitempty"`
+}
+
+// PrefixRule is a Rule that applies to the reasons of Code under Prefix.
+type PrefixRule struct {
+	Code   string `json:"code"`
+	Prefix string `json:"prefix"`
+	Rule
+}
+
+// ExactRule is a Rule that applies to exactly Reason of Code.
+type ExactRule struct {
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+	Rule
+}
+
+// GrammarConfig is the file form of reason.Grammar.
+type GrammarConfig struct {
+	MaxDepth   int  `json:"max_depth,omitempty"`
+	MinLength  int  `json:"min_length,omitempty"`
+	MaxLength  int  `json:"max_length,omitempty"`
+	DigitFirst bool `json:"digit_first,omitempty"`
+}
+
+// ReverseConfig lists the preferred codes for FromHTTP and FromGRPC.
+type ReverseConfig struct {
+	HTTP []string `json:"http,omitempty"`
+	GRPC []string `json:"grpc,omitempty"`
+}
+
+// ConfigError reports an invalid configuration document. Line is 1-based and
+// zero when unknown; Rule is the path of the offending rule, e.g.
+// "defaults.unavailable" or "prefixes.2".
+type ConfigError struct {
+	File string
+	Line int
+	Rule string
+	Err  error
+}
+
+// Error implements the error interface as "mapper: file:line: rule: err".
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+	b.WriteString("mapper: ")
+	switch {
+	case e.File != "" && e.Line > 0:
+		_, _ = fmt.Fprintf(&b, "%s:%d: ", e.File, e.Line)
+	case e.File != "":
+		b.WriteString(e.File + ": ")
+	case e.Line > 0:
+		_, _ = fmt.Fprintf(&b, "line %d: ", e.Line)
+	}
+	if e.Rule != "" {
+		b.WriteString(e.Rule + ": ")
+	}
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// FromConfig builds a mapper from a JSON document (see Config). The opts are
+// applied before the document's rules, so the document wins on conflicts.
+//
+// Errors describing the document are *ConfigError values.
+func FromConfig(r io.Reader, opts ...Option) (apis.Mapper, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("mapper: read config: %w", err)
+	}
+	return fromConfigData(data, "", opts)
+}
+
+// LoadFile is FromConfig for the file at path; errors name the file.
+func LoadFile(path string, opts ...Option) (apis.Mapper, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mapper: %w", err)
+	}
+	return fromConfigData(data, path, opts)
+}
+
+// ReadConfig decodes and validates a JSON document without building a mapper.
+func ReadConfig(r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("mapper: read config: %w", err)
+	}
+	return decodeConfig(data, reason.DefaultGrammar)
+}
+
+// fromConfigData decodes data, naming file in errors, and builds the mapper.
+func fromConfigData(data []byte, file string, opts []Option) (apis.Mapper, error) {
+	b := newBuilder()
+	for _, opt := range opts {
+		opt(b)
+	}
+	cfg, err := decodeConfig(data, b.grammar)
+	if err != nil {
+		var ce *ConfigError
+		if errors.As(err, &ce) {
+			ce.File = file
+		}
+		return nil, err
+	}
+	return New(append(slices.Clip(opts), cfg.Options()...)...)
+}
+
+// Options converts the document into mapper options, in the order
+// grammar, fallback, defaults, overrides, exact rules, prefixes, reverse
+// preferences.
+// The document is expected to be valid; see ReadConfig.
+func (c *Config) Options() []Option {
+	var opts []Option
+	if p, ok := parsePrecedence(c.Precedence); ok {
+		opts = append(opts, WithPrecedence(p))
+	}
+	if g := c.Grammar; g != nil {
+		opts = append(opts, WithGrammar(reason.Grammar{
+			MaxDepth:   g.MaxDepth,
+			MinLength:  g.MinLength,
+			MaxLength:  g.MaxLength,
+			DigitFirst: g.DigitFirst,
+		}))
+	}
+	if f := c.Fallback; f != nil {
+		if f.HTTP != 0 {
+			opts = append(opts, WithHTTPFallback(f.HTTP))
+		}
+		if v, ok := grpcCodeByName(f.GRPC); ok {
+			opts = append(opts, WithGRPCFallback(int(v)))
+		}
+	}
+	for _, k := range sortedKeys(c.Defaults) {
+		r := c.Defaults[k]
+		if r.HTTP != 0 {
+			opts = append(opts, WithHTTPDefault(code.Code(k), r.HTTP))
+		}
+		if v, ok := grpcCodeByName(r.GRPC); ok {
+			opts = append(opts, WithGRPCDefault(code.Code(k), int(v)))
+		}
+	}
+	for _, k := range sortedKeys(c.Overrides) {
+		r := c.Overrides[k]
+		if r.HTTP != 0 {
+			opts = append(opts, WithHTTPOverride(code.Code(k), r.HTTP))
+		}
+		if v, ok := grpcCodeByName(r.GRPC); ok {
+			opts = append(opts, WithGRPCOverride(code.Code(k), int(v)))
+		}
+	}
+	for _, x := range c.Exact {
+		if x.HTTP != 0 {
+			opts = append(opts, WithHTTPExact(code.Code(x.Code), x.Reason, x.HTTP))
+		}
+		if v, ok := grpcCodeByName(x.GRPC); ok {
+			opts = append(opts, WithGRPCExact(code.Code(x.Code), x.Reason, int(v)))
+		}
+	}
+	for _, p := range c.Prefixes {
+		if p.HTTP != 0 {
+			opts = append(opts, WithHTTPPrefix(code.Code(p.Code), p.Prefix, p.HTTP))
+		}
+		if v, ok := grpcCodeByName(p.GRPC); ok {
+			opts = append(opts, WithGRPCPrefix(code.Code(p.Code), p.Prefix, int(v)))
+		}
+	}
+	if rv := c.Reverse; rv != nil {
+		for _, s := range rv.HTTP {
+			opts = append(opts, WithHTTPReversePreference(code.Code(s)))
+		}
+		for _, s := range rv.GRPC {
+			opts = append(opts, WithGRPCReversePreference(code.Code(s)))
+		}
+	}
+	return opts
+}
+
+// ConfigOf captures the rules set by opts as a Config, so mappers built in
+// Go can be dumped to a file. Library defaults are not included, nor is an
+// unknown precedence, which New rejects.
+func ConfigOf(opts ...Option) *Config {
+	b := newBuilder()
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	c := &Config{
+		Version:  ConfigVersion,
+		Fallback: &Rule{HTTP: b.fallbackHTTP, GRPC: grpcCodeName(b.fallbackGRPC)},
+	}
+	if b.precedence == OverrideFirst {
+		c.Precedence = b.precedence.String()
+	}
+	if b.grammar != reason.DefaultGrammar {
+		c.Grammar = &GrammarConfig{
+			MaxDepth:   b.grammar.MaxDepth,
+			MinLength:  b.grammar.MinLength,
+			MaxLength:  b.grammar.MaxLength,
+			DigitFirst: b.grammar.DigitFirst,
+		}
+	}
+	c.Defaults = ruleMap(b.httpDefaults, b.grpcDefaults)
+	c.Overrides = ruleMap(b.httpOverride, b.grpcOverride)
+
+	for _, p := range mergeRules(b.httpExact, b.grpcExact) {
+		c.Exact = append(c.Exact, ExactRule{Code: p.Code, Reason: p.Prefix, Rule: p.Rule})
+	}
+	c.Prefixes = mergeRules(b.httpPrefixes, b.grpcPrefixes)
+
+	if len(b.httpReversePref) > 0 || len(b.grpcReversePref) > 0 {
+		c.Reverse = &ReverseConfig{
+			HTTP: codeStrings(b.httpReversePref),
+			GRPC: codeStrings(b.grpcReversePref),
+		}
+	}
+	return c
+}
+
+// WriteConfig writes the rules set by opts as an indented JSON document that
+// FromConfig and LoadFile read back into an equivalent mapper.
+func WriteConfig(w io.Writer, opts ...Option) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ConfigOf(opts...))
+}
+
+// decodeConfig parses and validates data. Prefixes are checked against the
+// document's grammar, or g when the document has none.
+func decodeConfig(data []byte, g reason.Grammar) (*Config, error) {
+	idx, err := indexConfig(data)
+	if err != nil {
+		var se *json.SyntaxError
+		if errors.As(err, &se) {
+			return nil, &ConfigError{Line: lineAt(data, se.Offset), Err: err}
+		}
+		return nil, &ConfigError{Err: err}
+	}
+
+	var c Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		ce := &ConfigError{Err: err}
+		var te *json.UnmarshalTypeError
+		if errors.As(err, &te) {
+			ce.Rule = ruleOf(te.Field)
+			ce.Line = idx.line(te.Field)
+		} else if s, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			if name, uerr := strconv.Unquote(s); uerr == nil {
+				path := idx.find(name)
+				ce.Rule = ruleOf(path)
+				ce.Line = idx.line(path)
+			}
+		}
+		return nil, ce
+	}
+
+	v := &configValidator{idx: idx}
+	v.validate(&c, g)
+	if v.err != nil {
+		return nil, v.err
+	}
+	return &c, nil
+}
+
+// configValidator checks a decoded Config and keeps the first error.
+type configValidator struct {
+	idx configIndex
+	err *ConfigError
+}
+
+func (v *configValidator) fail(path, format string, args ...any) {
+	if v.err == nil {
+		v.err = &ConfigError{Line: v.idx.line(path), Rule: path, Err: fmt.Errorf(format, args...)}
+	}
+}
+
+func (v *configValidator) validate(c *Config, g reason.Grammar) {
+	switch c.Version {
+	case ConfigVersion:
+	case 0:
+		v.fail("version", "missing version")
+	default:
+		v.fail("version", "unsupported version %d (want %d)", c.Version, ConfigVersion)
+	}
+
+	if _, ok := parsePrecedence(c.Precedence); c.Precedence != "" && !ok {
+		v.fail("precedence", "unknown precedence %q", c.Precedence)
+	}
+
+	if cg := c.Grammar; cg != nil {
+		if cg.MaxDepth < 0 || cg.MinLength < 0 || cg.MaxLength < 0 {
+			v.fail("grammar", "limits must not be negative")
+		}
+		g = reason.Grammar{MaxDepth: cg.MaxDepth, MinLength: cg.MinLength, MaxLength: cg.MaxLength, DigitFirst: cg.DigitFirst}
+	}
+
+	if c.Fallback != nil {
+		v.rule("fallback", *c.Fallback)
+	}
+	for _, k := range sortedKeys(c.Defaults) {
+		path := "defaults." + k
+		v.code(path, k)
+		v.rule(path, c.Defaults[k])
+	}
+	for _, k := range sortedKeys(c.Overrides) {
+		path := "overrides." + k
+		v.code(path, k)
+		v.rule(path, c.Overrides[k])
+	}
+	for i, x := range c.Exact {
+		path := "exact." + strconv.Itoa(i)
+		v.code(path, x.Code)
+		if r, err := g.Parse(x.Reason); err != nil || r == reason.Empty {
+			v.fail(path, "invalid exact reason %q", x.Reason)
+		}
+		v.rule(path, x.Rule)
+	}
+	for i, p := range c.Prefixes {
+		path := "prefixes." + strconv.Itoa(i)
+		v.code(path, p.Code)
+		if _, err := normalizeAndValidatePrefix(g, p.Prefix); err != nil {
+			v.fail(path, "invalid reason-prefix %q: %w", p.Prefix, err)
+		}
+		v.rule(path, p.Rule)
+	}
+	if rv := c.Reverse; rv != nil {
+		for i, s := range rv.HTTP {
+			v.code("reverse.http."+strconv.Itoa(i), s)
+		}
+		for i, s := range rv.GRPC {
+			v.code("reverse.grpc."+strconv.Itoa(i), s)
+		}
+	}
+}
+
+// code checks that s is a canonical code (registered, in strict mode).
+func (v *configValidator) code(path, s string) {
+	c, err := code.Parse(s)
+	switch {
+	case err != nil:
+		v.fail(path, "code %q: %w", s, err)
+	case string(c) != s:
+		v.fail(path, "code %q is not canonical (want %q)", s, c)
+	}
+}
+
+// rule checks that r sets a valid HTTP status, a known gRPC name, or both.
+func (v *configValidator) rule(path string, r Rule) {
+	if r.HTTP == 0 && r.GRPC == "" {
+		v.fail(path, "rule sets neither http nor grpc")
+	}
+	if r.HTTP != 0 && (r.HTTP < 100 || r.HTTP > 599) {
+		v.fail(path, "invalid HTTP status %d", r.HTTP)
+	}
+	if _, ok := grpcCodeByName(r.GRPC); r.GRPC != "" && !ok {
+		v.fail(path, "unknown gRPC code %q", r.GRPC)
+	}
+}
+
+// configIndex maps value paths in the encoding/json field syntax
+// ("prefixes.2.http") to the byte offset of the value, so that errors can
+// name a line.
+type configIndex struct {
+	data []byte
+	offs map[string]int64
+}
+
+// indexConfig walks data token by token and records where every value
+// starts. It also reports syntax errors and trailing data.
+func indexConfig(data []byte) (configIndex, error) {
+	idx := configIndex{data: data, offs: make(map[string]int64)}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(path string) error
+	walk = func(path string) error {
+		if path != "" {
+			idx.offs[path] = dec.InputOffset()
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		d, ok := tok.(json.Delim)
+		if !ok {
+			return nil
+		}
+		for i := 0; dec.More(); i++ {
+			key := strconv.Itoa(i)
+			if d == '{' {
+				k, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key = k.(string)
+			}
+			if path != "" {
+				key = path + "." + key
+			}
+			if err := walk(key); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token() // closing delimiter
+		return err
+	}
+
+	if err := walk(""); err != nil {
+		if err == io.EOF {
+			err = errors.New("empty document")
+		}
+		return idx, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return idx, errors.New("unexpected data after the document")
+	}
+	return idx, nil
+}
+
+// line returns the 1-based line of the value at path, or 0 if unknown.
+func (x configIndex) line(path string) int {
+	off, ok := x.offs[path]
+	if !ok {
+		return 0
+	}
+	return lineAt(x.data, off)
+}
+
+// find returns the first path, in document order, whose last element is name.
+func (x configIndex) find(name string) string {
+	best, bestOff := "", int64(-1)
+	for p, off := range x.offs {
+		if p == name || strings.HasSuffix(p, "."+name) {
+			if bestOff < 0 || off < bestOff {
+				best, bestOff = p, off
+			}
+		}
+	}
+	return best
+}
+
+// lineAt returns the 1-based line of the first token at or after off.
+// Decoder offsets point just past the previous token, so separators are
+// skipped first.
+func lineAt(data []byte, off int64) int {
+	for off < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[off]) >= 0 {
+		off++
+	}
+	return 1 + bytes.Count(data[:min(off, int64(len(data)))], []byte{'\n'})
+}
+
+// ruleOf trims a value path to the rule it belongs to: two elements for the
+// rule collections ("defaults.unavailable"), one otherwise ("fallback").
+func ruleOf(path string) string {
+	parts := strings.SplitN(path, ".", 3)
+	switch parts[0] {
+	case "defaults", "overrides", "exact", "prefixes":
+		return strings.Join(parts[:min(len(parts), 2)], ".")
+	}
+	return parts[0]
+}
+
+// grpcNames are the canonical names of the gRPC status codes, as used by
+// the gRPC specification and the JSON form of codes.Code.
+var grpcNames = [...]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// grpcCodeName returns the canonical name of c, or its number for codes
+// outside the standard range.
+func grpcCodeName(c codes.Code) string {
+	if int(c) < len(grpcNames) {
+		return grpcNames[c]
+	}
+	return strconv.Itoa(int(c))
+}
+
+// grpcCodeByName is the inverse of grpcCodeName; names are case-insensitive.
+func grpcCodeByName(s string) (codes.Code, bool) {
+	if s == "" {
+		return 0, false
+	}
+	if i := slices.Index(grpcNames[:], strings.ToUpper(s)); i >= 0 {
+		return codes.Code(i), true
+	}
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return codes.Code(n), true
+	}
+	return 0, false
+}
+
+// ruleMap merges per-code HTTP and gRPC values into Rules keyed by code.
+func ruleMap(httpVals, grpcVals map[code.Code]int) map[string]Rule {
+	if len(httpVals) == 0 && len(grpcVals) == 0 {
+		return nil
+	}
+	out := make(map[string]Rule, len(httpVals))
+	for k, v := range httpVals {
+		r := out[string(k)]
+		r.HTTP = v
+		out[string(k)] = r
+	}
+	for k, v := range grpcVals {
+		r := out[string(k)]
+		r.GRPC = grpcCodeName(codes.Code(v))
+		out[string(k)] = r
+	}
+	return out
+}
+
+// mergeRules lists per-code HTTP and gRPC rules ordered by code, then by
+// declaration. HTTP and gRPC rules for the same code and pattern share one
+// entry.
+func mergeRules(httpRules, grpcRules map[code.Code][]prefixRule) []PrefixRule {
+	var cs []code.Code
+	for k := range httpRules {
+		cs = append(cs, k)
+	}
+	for k := range grpcRules {
+		if _, ok := httpRules[k]; !ok {
+			cs = append(cs, k)
+		}
+	}
+	slices.Sort(cs)
+
+	var out []PrefixRule
+	for _, k := range cs {
+		start := len(out)
+		for _, r := range httpRules[k] {
+			out = append(out, PrefixRule{Code: string(k), Prefix: r.prefix, Rule: Rule{HTTP: r.val}})
+		}
+	grpc:
+		for _, r := range grpcRules[k] {
+			name := grpcCodeName(codes.Code(r.val))
+			for i := start; i < len(out); i++ {
+				if p := &out[i]; p.Prefix == r.prefix && p.GRPC == "" {
+					p.GRPC = name
+					continue grpc
+				}
+			}
+			out = append(out, PrefixRule{Code: string(k), Prefix: r.prefix, Rule: Rule{GRPC: name}})
+		}
+	}
+	return out
+}
+
+// codeStrings converts codes to their string form.
+func codeStrings(cs []code.Code) []string {
+	if len(cs) == 0 {
+		return nil
+	}
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = string(c)
+	}
+	return out
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
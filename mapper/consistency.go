@@ -0,0 +1,225 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrInconsistent is wrapped by Report.Err when a mapper pairs HTTP statuses
+// with incompatible gRPC codes.
+var ErrInconsistent = errors.New("mapper: inconsistent HTTP/gRPC statuses")
+
+// compatTable lists, per gRPC code, the canonical HTTP status used by
+// grpc-gateway (runtime.HTTPStatusFromCode) first, followed by the other
+// statuses that are conventionally paired with it.
+var compatTable = map[codes.Code][]int{
+	codes.OK:                 {http.StatusOK}, // any 2xx, see Compatible
+	codes.Canceled:           {499, http.StatusRequestTimeout},
+	codes.Unknown:            {http.StatusInternalServerError},
+	codes.InvalidArgument:    {http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity},
+	codes.DeadlineExceeded:   {http.StatusGatewayTimeout, http.StatusRequestTimeout},
+	codes.NotFound:           {http.StatusNotFound, http.StatusGone},
+	codes.AlreadyExists:      {http.StatusConflict},
+	codes.PermissionDenied:   {http.StatusForbidden},
+	codes.ResourceExhausted:  {http.StatusTooManyRequests},
+	codes.FailedPrecondition: {http.StatusBadRequest, http.StatusConflict, http.StatusPreconditionFailed, http.StatusTooEarly, http.StatusPreconditionRequired},
+	codes.Aborted:            {http.StatusConflict},
+	codes.OutOfRange:         {http.StatusBadRequest, http.StatusRequestedRangeNotSatisfiable},
+	codes.Unimplemented:      {http.StatusNotImplemented, http.StatusMethodNotAllowed},
+	codes.Internal:           {http.StatusInternalServerError},
+	codes.Unavailable:        {http.StatusServiceUnavailable, http.StatusBadGateway},
+	codes.DataLoss:           {http.StatusInternalServerError},
+	codes.Unauthenticated:    {http.StatusUnauthorized},
+}
+
+// CanonicalHTTP returns the HTTP status grpc-gateway uses for c, or 500 for
+// codes outside the standard range.
+func CanonicalHTTP(c codes.Code) int {
+	if hs, ok := compatTable[c]; ok {
+		return hs[0]
+	}
+	return http.StatusInternalServerError
+}
+
+// Compatible reports whether status and c may describe the same error: status
+// is the canonical HTTP status for c or one of its conventional alternatives
+// (e.g. 410 for NotFound, 412 for FailedPrecondition). Any 2xx is compatible
+// with OK.
+func Compatible(status int, c codes.Code) bool {
+	if c == codes.OK {
+		return status >= 200 && status < 300
+	}
+	return slices.Contains(compatTable[c], status)
+}
+
+// Inconsistency is one incompatible (HTTP, gRPC) pair found by
+// CheckConsistency.
+type Inconsistency struct {
+	Code   code.Code
+	Reason reason.Reason
+
+	// Pattern is the mapper rule the reason was derived from, if any.
+	Pattern string
+
+	HTTP int
+	GRPC codes.Code
+}
+
+// String renders the inconsistency on one line.
+func (i Inconsistency) String() string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "code %q", i.Code)
+	if i.Pattern != "" {
+		_, _ = fmt.Fprintf(&b, " rule %q", i.Pattern)
+	} else if i.Reason != reason.Empty {
+		_, _ = fmt.Fprintf(&b, " reason %q", i.Reason)
+	}
+	_, _ = fmt.Fprintf(&b, ": HTTP %d with gRPC %s (canonical HTTP %d)", i.HTTP, grpcCodeName(i.GRPC), CanonicalHTTP(i.GRPC))
+	return b.String()
+}
+
+// Report is the result of CheckConsistency.
+type Report struct {
+	// Checked is the number of (code, reason) pairs resolved.
+	Checked int
+
+	// Inconsistencies lists the incompatible pairs, ordered by code.
+	Inconsistencies []Inconsistency
+}
+
+// OK reports whether no inconsistency was found.
+func (r Report) OK() bool { return len(r.Inconsistencies) == 0 }
+
+// Err returns nil for a clean report and otherwise an error wrapping
+// ErrInconsistent that lists every inconsistency. It is convenient in tests:
+//
+//	if err := mapper.CheckConsistency(m, nil, nil).Err(); err != nil {
+//	    t.Fatal(err)
+//	}
+func (r Report) Err() error {
+	if r.OK() {
+		return nil
+	}
+	msgs := make([]string, len(r.Inconsistencies))
+	for i, in := range r.Inconsistencies {
+		msgs[i] = in.String()
+	}
+	return fmt.Errorf("%w: %s", ErrInconsistent, strings.Join(msgs, "; "))
+}
+
+// CheckConsistency resolves every code in cs, with no reason and with each
+// reason in rs, and reports the pairs whose HTTP status and gRPC code are not
+// Compatible.
+//
+// A nil cs means every registered code (code.All). A nil rs means the rules
+// registered in m: for each code, one reason per exact and prefix rule, with
+// "*" standing for an arbitrary segment. Mappers other than those built by
+// this package (or a Reloadable serving one) have no known rules, so only
+// the code-level statuses are checked.
+//
+// Resolution goes through apis.Explainer when m implements it, so OnFallback
+// hooks are not triggered.
+func CheckConsistency(m apis.Mapper, cs []code.Code, rs []reason.Reason) Report {
+	if cs == nil {
+		cs = slices.Collect(code.All())
+	}
+	resolve := m.Status
+	if x, ok := m.(apis.Explainer); ok {
+		resolve = func(c code.Code, r reason.Reason) apis.Status { return x.Resolve(c, r).Status() }
+	}
+	impl := snapshotOf(m)
+
+	var rep Report
+	for _, c := range cs {
+		type pair struct {
+			http int
+			grpc codes.Code
+		}
+		seen := make(map[pair]bool)
+		check := func(r reason.Reason, pattern string) {
+			rep.Checked++
+			st := resolve(c, r)
+			if Compatible(st.HTTP, st.GRPC) || seen[pair{st.HTTP, st.GRPC}] {
+				return
+			}
+			seen[pair{st.HTTP, st.GRPC}] = true
+			rep.Inconsistencies = append(rep.Inconsistencies, Inconsistency{
+				Code: c, Reason: r, Pattern: pattern, HTTP: st.HTTP, GRPC: st.GRPC,
+			})
+		}
+
+		check(reason.Empty, "")
+		switch {
+		case rs != nil:
+			for _, r := range rs {
+				check(r, "")
+			}
+		case impl != nil:
+			for _, p := range impl.patterns(c) {
+				if r, ok := sampleReason(impl.grammar, p); ok {
+					check(r, p)
+				}
+			}
+		}
+	}
+	slices.SortStableFunc(rep.Inconsistencies, func(a, b Inconsistency) int {
+		return strings.Compare(string(a.Code), string(b.Code))
+	})
+	return rep
+}
+
+// snapshotOf returns the *mapper behind m, looking through a Reloadable.
+func snapshotOf(m apis.Mapper) *mapper {
+	if r, ok := m.(*Reloadable); ok {
+		m = r.Current()
+	}
+	impl, _ := m.(*mapper)
+	return impl
+}
+
+// patterns returns the exact reasons and prefix patterns registered for c
+// on either transport, sorted and without duplicates.
+func (m *mapper) patterns(c code.Code) []string {
+	var out []string
+	for r := range m.httpExact[c] {
+		out = append(out, string(r))
+	}
+	for r := range m.grpcExact[c] {
+		out = append(out, string(r))
+	}
+	out = append(out, m.httpTrie[c].Patterns()...)
+	out = append(out, m.grpcTrie[c].Patterns()...)
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// sampleReason turns a rule pattern into a reason it matches, replacing each
+// "*" with an arbitrary segment.
+func sampleReason(g reason.Grammar, pattern string) (reason.Reason, bool) {
+	r, err := g.Parse(strings.ReplaceAll(pattern, "*", "x"))
+	return r, err == nil && r != reason.Empty
+}
@@ -19,7 +19,7 @@ package mapper
 import (
 	"net/http"
 
-	"dirpx.dev/derrors/code"
+	"dirpx.dev/derrors/v2/code"
 	"google.golang.org/grpc/codes"
 )
 
@@ -15,8 +15,8 @@
 */
 
 // Package mapper provides deterministic, immutable mappings from logical
-// derrors codes (dirpx.dev/derrors/code) and optional reasons
-// (dirpx.dev/derrors/reason) to transport-level statuses for HTTP and gRPC.
+// derrors codes (dirpx.dev/derrors/v2/code) and optional reasons
+// (dirpx.dev/derrors/v2/reason) to transport-level statuses for HTTP and gRPC.
 //
 // # Overview
 //
@@ -38,10 +38,16 @@
 //
 // A Mapper resolves statuses in the following order:
 //
-//  1. exact override for the Code;
+//  1. per-Code exact-Reason rule (WithHTTPExact), a plain map lookup;
 //  2. per-Code longest-prefix-match (LPM) on the Reason;
-//  3. per-Code default (library or user-adjusted);
-//  4. global fallback (500 / codes.Internal).
+//  3. per-Code override;
+//  4. per-Code default (library or user-adjusted);
+//  5. global fallback (500 / codes.Internal, see WithHTTPFallback and
+//     WithGRPCFallback).
+//
+// This order is PrefixFirst, the default: reason rules refine a code-wide
+// override. WithPrecedence(OverrideFirst) moves the override to the top so
+// that it applies whatever the reason.
 //
 // Prefix rules are segment-aware: reasons are treated as "."-separated segments,
 // and "*" matches exactly one segment. For example:
@@ -49,7 +55,9 @@
 //	WithHTTPPrefix(code.Unavailable, "storage.pg", http.StatusServiceUnavailable)
 //	WithHTTPPrefix(code.Unavailable, "storage.*.connect", http.StatusServiceUnavailable)
 //
-// The more specific prefix wins.
+// The more specific prefix wins. Use an exact rule when a status must apply
+// to one reason only and not to the reasons below it. Prefixes use the reason.Pattern grammar;
+// "**" is not needed since every prefix already covers the reasons below it.
 //
 // # Library defaults
 //
@@ -76,10 +84,53 @@
 // # Diagnostics
 //
 // For debugging and tests, Mapper.Explain returns a human-readable trace of how
-// a particular (code, reason) was resolved, including which tier matched and, for
-// prefixes, which pattern was used.
+// a particular (code, reason) was resolved, including the active precedence,
+// which tier matched and, for prefixes, which pattern was used.
 //
 // This is intended for inspection and logging, not for stable machine parsing.
+// The same information is available as data through Resolve (apis.Explainer):
+// per transport, the winning tier, pattern and value, plus the rules that
+// matched but lost.
+//
+// # Linting
+//
+// Lint reports rules that are invalid, registered twice, dead (they can
+// never fire), redundant (they change nothing) or inconsistent across
+// transports (HTTP 404 with gRPC Internal), each with a Severity. New with
+// WithStrict runs the same checks and fails on SeverityError findings.
+//
+// # Consistency
+//
+// Compatible tells whether an HTTP status and a gRPC code may describe the
+// same error, following the grpc-gateway table (CanonicalHTTP) plus a few
+// conventional alternatives such as 410 for NotFound. CheckConsistency
+// applies it to a built mapper, for every code and every registered rule,
+// and is meant to run as a test in CI:
+//
+//	if err := mapper.CheckConsistency(m, nil, nil).Err(); err != nil {
+//	    t.Fatal(err)
+//	}
+//
+// # Reverse mapping
+//
+// Mappers built by New also implement apis.ReverseMapper: FromHTTP and
+// FromGRPC derive a code from a transport status using an inverse index of
+// the code-level rules. When several codes share a status, the preference
+// lists set with WithHTTPReversePreference / WithGRPCReversePreference are
+// consulted first, then a library order that favors the most generic code
+// (e.g. 400 -> code.Invalid, 409 -> code.Conflict).
+//
+// # Configuration files
+//
+// The rules can also be kept in a versioned JSON document (see Config).
+// FromConfig and LoadFile read it into the same immutable mapper New builds;
+// invalid documents yield a *ConfigError naming the file, line and rule.
+// WriteConfig goes the other way and dumps the rules of New(opts...).
+// YAML is not supported; convert it to JSON first.
+//
+// Reloadable wraps such a source: it serves the current snapshot through an
+// atomic.Pointer and swaps in a new one on Reload or when Watch sees the
+// file change, keeping the last good snapshot if a reload fails.
 //
 // # Immutability
 //
@@ -23,23 +23,50 @@ import (
 	"strings"
 	"testing"
 
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 	"google.golang.org/grpc/codes"
 )
 
 var update = flag.Bool("update", false, "update golden files")
 
-// TestExplain_Golden verifies Explain() output is stable and human-friendly.
+// TestExplain_Golden verifies Explain() output is stable and human-friendly,
+// once per precedence.
 // Update golden with: go test ./derrors/mapper -run Explain_Golden -update
 func TestExplain_Golden(t *testing.T) {
+	for _, tc := range []struct {
+		precedence Precedence
+		golden     string
+	}{
+		{PrefixFirst, "explain.golden"},
+		{OverrideFirst, "explain_override_first.golden"},
+	} {
+		t.Run(tc.precedence.String(), func(t *testing.T) {
+			testExplainGolden(t, tc.precedence, tc.golden)
+		})
+	}
+}
+
+func testExplainGolden(t *testing.T, p Precedence, golden string) {
 	m, err := New(
+		WithPrecedence(p),
 		// prefix rules
 		WithHTTPPrefix(code.Unavailable, "storage.pg", 503),
 		WithGRPCPrefix(code.Unavailable, "storage.pg", int(codes.Unavailable)),
 		// exact override rules
 		WithHTTPOverride(code.Canceled, 408),
 		WithGRPCOverride(code.Canceled, int(codes.Canceled)),
+		// exact reason rules
+		WithHTTPExact(code.Unauthenticated, "auth.jwt", 401),
+		WithGRPCExact(code.Unauthenticated, "auth.jwt", int(codes.Unauthenticated)),
+		// override and prefix rule for the same code
+		WithHTTPOverride(code.Conflict, 423),
+		WithGRPCOverride(code.Conflict, int(codes.FailedPrecondition)),
+		WithHTTPPrefix(code.Conflict, "lock.held", 409),
+		WithGRPCPrefix(code.Conflict, "lock.held", int(codes.Aborted)),
+		// global fallback
+		WithHTTPFallback(599),
+		WithGRPCFallback(int(codes.Unknown)),
 	)
 	if err != nil {
 		t.Fatalf("New: %v", err)
@@ -55,11 +82,26 @@ func TestExplain_Golden(t *testing.T) {
 	// Case 2: override
 	exp2 := m.Explain(code.Canceled, reason.Empty)
 	b.WriteString(exp2)
+	b.WriteString("\n---\n")
+
+	// Case 3: exact reason
+	exp3 := m.Explain(code.Unauthenticated, mustReason("auth.jwt"))
+	b.WriteString(exp3)
+	b.WriteString("\n---\n")
+
+	// Case 4: override vs prefix, decided by precedence
+	exp4 := m.Explain(code.Conflict, mustReason("lock.held.by_peer"))
+	b.WriteString(exp4)
+	b.WriteString("\n---\n")
+
+	// Case 5: unmapped code, global fallback
+	exp5 := m.Explain("unmapped", reason.Empty)
+	b.WriteString(exp5)
 	b.WriteString("\n")
 
 	got := b.String()
 
-	goldenPath := filepath.Join("testdata", "explain.golden")
+	goldenPath := filepath.Join("testdata", golden)
 	if *update {
 		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
 			t.Fatalf("mkdir testdata: %v", err)
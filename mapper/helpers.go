@@ -17,8 +17,8 @@
 package mapper
 
 import (
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/mapper/internal/segmenttrie"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/mapper/internal/segmenttrie"
 	"google.golang.org/grpc/codes"
 )
 
@@ -18,7 +18,10 @@ package segmenttrie
 
 import (
 	"errors"
+	"slices"
 	"strings"
+
+	"dirpx.dev/derrors/v2/reason"
 )
 
 // Trie is a segment-aware prefix index for dot-separated keys (reasons).
@@ -35,6 +38,9 @@ type Trie[T any] struct {
 	// for this node, set only when hasVal=true. It is used by MatchWithPattern
 	// for Explain(), so we don't build strings during lookup.
 	pattern string
+	// grammar defines valid segments and the maximum depth. Only the root's
+	// grammar is consulted.
+	grammar reason.Grammar
 }
 
 var (
@@ -43,11 +49,17 @@ var (
 	ErrInvalidPrefix = errors.New("segmenttrie: invalid prefix")
 )
 
-// New creates an empty trie ready for inserts.
+// New creates an empty trie ready for inserts, using reason.DefaultGrammar.
 func New[T any]() *Trie[T] {
 	return &Trie[T]{children: make(map[string]*Trie[T])}
 }
 
+// NewWithGrammar creates an empty trie whose prefixes and matched reasons
+// follow g.
+func NewWithGrammar[T any](g reason.Grammar) *Trie[T] {
+	return &Trie[T]{children: make(map[string]*Trie[T]), grammar: g}
+}
+
 // Insert adds a dot-separated prefix to the trie and associates it with val.
 //
 // Examples:
@@ -56,15 +68,17 @@ func New[T any]() *Trie[T] {
 //	"auth.jwt.verify"
 //	"auth.*.verify"
 //
-// The wildcard "*" matches exactly one segment.
+// The wildcard "*" matches exactly one segment. Other segments and the
+// maximum depth follow the trie's grammar.
 // A prefix made only of "*" segments is rejected, because it is too generic.
+// Inserting a prefix that is already present replaces its value.
 // Returns ErrInvalidPrefix on malformed input.
 func (t *Trie[T]) Insert(prefix string, val T) error {
 	if t == nil {
 		return ErrInvalidPrefix
 	}
-	segs, ok := splitAndValidate(prefix, true /* allowWildcard */)
-	if !ok || len(segs) == 0 {
+	segs, ok := splitAndValidate(t.grammar, prefix, true /* allowWildcard */)
+	if !ok || len(segs) == 0 || len(segs) > t.grammar.Resolved().MaxDepth {
 		return ErrInvalidPrefix
 	}
 
@@ -89,12 +103,11 @@ func (t *Trie[T]) Insert(prefix string, val T) error {
 		}
 		cur = child
 	}
+	// Re-inserting a prefix replaces both the value and the pattern, so that
+	// MatchWithPattern keeps reporting the rule that actually wins.
 	cur.hasVal = true
 	cur.val = val
-	if cur.pattern == "" {
-		// build pattern once; cost is at build time, not on hot path
-		cur.pattern = prefix
-	}
+	cur.pattern = prefix
 	return nil
 }
 
@@ -124,41 +137,17 @@ func (t *Trie[T]) Match(reason string) (T, bool) {
 			return depth
 		}
 
-		// parse next segment [off:next), validating [a-z][a-z0-9_]*
-		i := off
-		// first char
-		c := reason[i]
-		if c < 'a' || c > 'z' {
+		seg, nextOff, ok := nextSegment(t.grammar, reason, off)
+		if !ok {
 			return depth // invalid segment => stop this path
 		}
-		i++
-		for i < len(reason) {
-			c = reason[i]
-			if c == '.' {
-				break
-			}
-			if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_') {
-				return depth // invalid char => stop
-			}
-			i++
-		}
-		seg := reason[off:i] // substring; no heap alloc
 
 		// exact branch
 		if next, ok := n.children[seg]; ok {
-			// skip '.' if present
-			nextOff := i
-			if nextOff < len(reason) && reason[nextOff] == '.' {
-				nextOff++
-			}
 			_ = dfs(next, nextOff, depth+1)
 		}
 		// wildcard branch
 		if next, ok := n.children["*"]; ok {
-			nextOff := i
-			if nextOff < len(reason) && reason[nextOff] == '.' {
-				nextOff++
-			}
 			_ = dfs(next, nextOff, depth+1)
 		}
 		return depth
@@ -193,28 +182,10 @@ func (t *Trie[T]) MatchWithPattern(reason string) (T, bool, string) {
 		if off >= len(reason) {
 			return
 		}
-		// parse next segment (same as in MatchValue)
-		i := off
-		c := reason[i]
-		if c < 'a' || c > 'z' {
+		seg, nextOff, ok := nextSegment(t.grammar, reason, off)
+		if !ok {
 			return
 		}
-		i++
-		for i < len(reason) {
-			c = reason[i]
-			if c == '.' {
-				break
-			}
-			if !((c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_') {
-				return
-			}
-			i++
-		}
-		seg := reason[off:i]
-		nextOff := i
-		if nextOff < len(reason) && reason[nextOff] == '.' {
-			nextOff++
-		}
 
 		if next, ok := n.children[seg]; ok {
 			dfs(next, nextOff, depth+1)
@@ -231,50 +202,113 @@ func (t *Trie[T]) MatchWithPattern(reason string) (T, bool, string) {
 	return bestVal, true, bestPat
 }
 
+// Hit is one rule matched by MatchAll.
+type Hit[T any] struct {
+	// Pattern is the rule as it was inserted (may contain "*").
+	Pattern string
+	// Value is the value stored with the rule.
+	Value T
+	// Depth is the number of segments of the rule.
+	Depth int
+}
+
+// MatchAll returns every rule that matches reason, best first: deeper rules
+// before shallower ones and, at equal depth, exact segments before "*".
+// The first hit is the one Match and MatchWithPattern return.
+//
+// Unlike Match it allocates; it is meant for diagnostics.
+func (t *Trie[T]) MatchAll(reason string) []Hit[T] {
+	if t == nil {
+		return nil
+	}
+	var hits []Hit[T]
+
+	var dfs func(n *Trie[T], off, depth int)
+	dfs = func(n *Trie[T], off, depth int) {
+		if n.hasVal {
+			hits = append(hits, Hit[T]{Pattern: n.pattern, Value: n.val, Depth: depth})
+		}
+		if off >= len(reason) {
+			return
+		}
+		seg, nextOff, ok := nextSegment(t.grammar, reason, off)
+		if !ok {
+			return
+		}
+
+		if next, ok := n.children[seg]; ok {
+			dfs(next, nextOff, depth+1)
+		}
+		if next, ok := n.children["*"]; ok {
+			dfs(next, nextOff, depth+1)
+		}
+	}
+
+	dfs(t, 0, 0)
+	// The traversal visits exact branches before wildcards, so a stable sort
+	// by depth keeps the tie-breaking of Match.
+	slices.SortStableFunc(hits, func(a, b Hit[T]) int { return b.Depth - a.Depth })
+	return hits
+}
+
+// Patterns returns the patterns of all rules in the trie, sorted.
+func (t *Trie[T]) Patterns() []string {
+	if t == nil {
+		return nil
+	}
+	var out []string
+	var walk func(n *Trie[T])
+	walk = func(n *Trie[T]) {
+		if n.hasVal {
+			out = append(out, n.pattern)
+		}
+		for _, child := range n.children {
+			walk(child)
+		}
+	}
+	walk(t)
+	slices.Sort(out)
+	return out
+}
+
 // splitAndValidate splits a dot-separated string into segments and validates
-// each segment according to validSegment(). When allowWildcard=true,
+// each segment according to validSegment() under g. When allowWildcard=true,
 // a segment that is exactly "*" is accepted.
 // Returns (segments, true) on success, or (nil, false) on invalid input.
 //
 // Note: an empty string is treated as an empty (but valid) segment list
 // to make matching against "" possible in callers.
-func splitAndValidate(s string, allowWildcard bool) ([]string, bool) {
+func splitAndValidate(g reason.Grammar, s string, allowWildcard bool) ([]string, bool) {
 	if s == "" {
 		return []string{}, true
 	}
 	segs := strings.Split(s, ".")
 	for _, seg := range segs {
-		if !validSegment(seg, allowWildcard) {
+		if !validSegment(g, seg, allowWildcard) {
 			return nil, false
 		}
 	}
 	return segs, true
 }
 
-// validSegment reports whether seg is a valid trie segment.
-// Rules:
-//   - empty segments are invalid;
-//   - when allowWildcard=true, the segment "*" is allowed;
-//   - otherwise the segment must match: [a-z][a-z0-9_]*
-//
-// These rules keep reason prefixes simple, predictable and easy to normalize.
-func validSegment(seg string, allowWildcard bool) bool {
-	if seg == "" {
-		return false
-	}
+// validSegment reports whether seg is a valid trie segment: a reason segment
+// under g or, when allowWildcard=true, "*".
+func validSegment(g reason.Grammar, seg string, allowWildcard bool) bool {
 	if allowWildcard && seg == "*" {
 		return true
 	}
-	// [a-z][a-z0-9_]*
-	if seg[0] < 'a' || seg[0] > 'z' {
-		return false
-	}
-	for i := 1; i < len(seg); i++ {
-		c := seg[i]
-		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
-			continue
-		}
-		return false
+	return g.ValidSegment(seg)
+}
+
+// nextSegment returns the segment of s that starts at byte offset off, the
+// offset of the segment after it, and whether the segment is valid under g.
+// It slices s and never allocates.
+func nextSegment(g reason.Grammar, s string, off int) (seg string, next int, ok bool) {
+	end := strings.IndexByte(s[off:], '.')
+	if end < 0 {
+		seg, next = s[off:], len(s)
+	} else {
+		seg, next = s[off:off+end], off+end+1
 	}
-	return true
+	return seg, next, g.ValidSegment(seg)
 }
@@ -97,3 +97,54 @@ func must(t *testing.T, err error) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestMatchAll_BestFirst(t *testing.T) {
+	tr := New[int]()
+	must(t, tr.Insert("auth", 1))
+	must(t, tr.Insert("auth.*.verify", 2))
+	must(t, tr.Insert("auth.jwt.verify", 3))
+	must(t, tr.Insert("auth.jwt", 4))
+	must(t, tr.Insert("billing", 5))
+
+	hits := tr.MatchAll("auth.jwt.verify.sig")
+	var got []string
+	for _, h := range hits {
+		got = append(got, h.Pattern)
+	}
+	want := []string{"auth.jwt.verify", "auth.*.verify", "auth.jwt", "auth"}
+	if len(got) != len(want) {
+		t.Fatalf("MatchAll = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("MatchAll = %q, want %q", got, want)
+		}
+	}
+	if v, _, p := tr.MatchWithPattern("auth.jwt.verify.sig"); hits[0].Value != v || hits[0].Pattern != p {
+		t.Fatalf("first hit %+v differs from MatchWithPattern (%v, %q)", hits[0], v, p)
+	}
+	if hits := tr.MatchAll("storage.pg"); hits != nil {
+		t.Fatalf("MatchAll(no match) = %+v, want nil", hits)
+	}
+}
+
+func TestInsert_DuplicateReplacesValueAndPattern(t *testing.T) {
+	tr := New[int]()
+	must(t, tr.Insert("auth.*", 1))
+	must(t, tr.Insert("auth.*", 2))
+	if v, ok, p := tr.MatchWithPattern("auth.jwt"); !ok || v != 2 || p != "auth.*" {
+		t.Fatalf("MatchWithPattern = (%v, %v, %q), want last value 2", v, ok, p)
+	}
+}
+
+func TestPatterns_Sorted(t *testing.T) {
+	tr := New[int]()
+	must(t, tr.Insert("storage.pg", 1))
+	must(t, tr.Insert("auth.*.verify", 2))
+	must(t, tr.Insert("auth", 3))
+	got := tr.Patterns()
+	want := []string{"auth", "auth.*.verify", "storage.pg"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Patterns() = %q, want %q", got, want)
+	}
+}
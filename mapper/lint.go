@@ -0,0 +1,427 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/mapper/internal/segmenttrie"
+	"dirpx.dev/derrors/v2/reason"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrLint is wrapped by the error New returns in strict mode (see
+// WithStrict) when Lint reports errors.
+var ErrLint = errors.New("mapper: lint failed")
+
+// Severity grades a lint Finding.
+type Severity uint8
+
+const (
+	// SeverityInfo marks observations that need no action.
+	SeverityInfo Severity = iota
+
+	// SeverityWarning marks rules that are harmless but likely unintended,
+	// e.g. redundant ones.
+	SeverityWarning
+
+	// SeverityError marks rules that do not do what they say: invalid,
+	// dead or conflicting ones. Strict mode rejects them.
+	SeverityError
+)
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// Lint checks.
+const (
+	CheckInvalid      = "invalid"      // rule fails validation; New rejects it
+	CheckDuplicate    = "duplicate"    // same rule registered twice; the last one wins
+	CheckDead         = "dead"         // rule can never fire
+	CheckRedundant    = "redundant"    // removing the rule changes nothing
+	CheckInconsistent = "inconsistent" // HTTP and gRPC statuses disagree
+)
+
+// Finding is one problem reported by Lint.
+type Finding struct {
+	Severity Severity
+	Check    string
+	Code     code.Code
+
+	// Rule describes the rule, e.g. `http prefix "storage.pg"`; it is empty
+	// for findings about a code as a whole.
+	Rule    string
+	Message string
+}
+
+// String renders the finding on one line.
+func (f Finding) String() string {
+	if f.Rule == "" {
+		return fmt.Sprintf("%s: %s: code %q: %s", f.Severity, f.Check, f.Code, f.Message)
+	}
+	return fmt.Sprintf("%s: %s: code %q: %s: %s", f.Severity, f.Check, f.Code, f.Rule, f.Message)
+}
+
+// Lint checks the rules set by opts, on top of the library defaults, and
+// returns its findings ordered by code:
+//
+//   - invalid: a reason or prefix New would reject;
+//   - duplicate: the same exact or prefix rule registered twice (an error
+//     when the statuses differ, since only the last one is used);
+//   - dead: a reason rule that can never fire, e.g. under OverrideFirst on
+//     a code with an override;
+//   - redundant: a rule that resolves to what the mapper would return
+//     without it, e.g. "storage.pg" -> 503 next to "storage.*" -> 503;
+//   - inconsistent: a (code, rule) whose HTTP and gRPC statuses are not
+//     Compatible, an error for HTTP 404 with gRPC Internal and a warning
+//     for HTTP 404 with gRPC InvalidArgument; only codes with user rules
+//     are checked.
+//
+// Lint is a build-time tool; New(WithStrict(), ...) applies it on the fly.
+func Lint(opts ...Option) []Finding {
+	b := newSeededBuilder(opts)
+	m, err := build(b)
+	if err != nil {
+		m = nil
+	}
+	return lint(b, m)
+}
+
+// lint runs the checks on a seeded builder. m is the mapper built from b, or
+// nil when b does not build; the consistency check is skipped then.
+func lint(b *builder, m *mapper) []Finding {
+	l := &linter{b: b}
+	l.transport("http", b.httpOverride, b.httpDefaults, b.httpExact, b.httpPrefixes, b.fallbackHTTP, strconv.Itoa)
+	l.transport("grpc", b.grpcOverride, b.grpcDefaults, b.grpcExact, b.grpcPrefixes, int(b.fallbackGRPC),
+		func(v int) string { return grpcCodeName(codes.Code(v)) })
+	if m != nil {
+		l.consistency(m)
+	}
+	slices.SortStableFunc(l.out, func(a, b Finding) int { return strings.Compare(string(a.Code), string(b.Code)) })
+	return l.out
+}
+
+// lintError turns the error findings into an error wrapping ErrLint.
+func lintError(fs []Finding) error {
+	var msgs []string
+	for _, f := range fs {
+		if f.Severity == SeverityError {
+			msgs = append(msgs, f.String())
+		}
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrLint, strings.Join(msgs, "; "))
+}
+
+// linter collects findings for one builder.
+type linter struct {
+	b   *builder
+	out []Finding
+
+	// patterns holds the effective reason rules per code, across both
+	// transports, for the consistency check.
+	patterns map[code.Code][]string
+}
+
+func (l *linter) add(sev Severity, check string, c code.Code, rule, format string, args ...any) {
+	l.out = append(l.out, Finding{Severity: sev, Check: check, Code: c, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+// lintRule is a validated reason rule.
+type lintRule struct {
+	pattern string
+	val     int
+}
+
+// transport runs the per-transport checks; format renders a status.
+func (l *linter) transport(name string, override, defaults map[code.Code]int, exact, prefixes map[code.Code][]prefixRule,
+	fallback int, format func(int) string) {
+	g := l.b.grammar
+
+	var cs []code.Code
+	for c := range exact {
+		cs = append(cs, c)
+	}
+	for c := range prefixes {
+		if _, ok := exact[c]; !ok {
+			cs = append(cs, c)
+		}
+	}
+	for c := range override {
+		if _, ok := exact[c]; !ok {
+			if _, ok := prefixes[c]; !ok {
+				cs = append(cs, c)
+			}
+		}
+	}
+	slices.Sort(cs)
+
+	for _, c := range cs {
+		exacts := l.effective(name+" exact", c, exact[c], format, func(raw string) (string, error) {
+			r, err := g.Parse(raw)
+			if err == nil && r == reason.Empty {
+				err = errors.New("reason is empty")
+			}
+			return string(r), err
+		})
+		prefs := l.effective(name+" prefix", c, prefixes[c], format, func(raw string) (string, error) {
+			return normalizeAndValidatePrefix(g, raw)
+		})
+		if l.patterns == nil {
+			l.patterns = make(map[code.Code][]string)
+		}
+		for _, r := range exacts {
+			l.patterns[c] = append(l.patterns[c], r.pattern)
+		}
+		for _, r := range prefs {
+			l.patterns[c] = append(l.patterns[c], r.pattern)
+		}
+
+		ov, hasOverride := override[c]
+
+		// Dead: an override that ranks first hides every reason rule.
+		if l.b.precedence == OverrideFirst && hasOverride {
+			for _, r := range exacts {
+				l.add(SeverityError, CheckDead, c, fmt.Sprintf("%s exact %q", name, r.pattern),
+					"never used: the %s override (%s) ranks first", name, format(ov))
+			}
+			for _, r := range prefs {
+				l.add(SeverityError, CheckDead, c, fmt.Sprintf("%s prefix %q", name, r.pattern),
+					"never used: the %s override (%s) ranks first", name, format(ov))
+			}
+			continue
+		}
+
+		// The code-level status a reason rule falls back to.
+		base, baseTier := fallback, "fallback"
+		switch d, hasDefault := defaults[c]; {
+		case hasOverride:
+			base, baseTier = ov, "override"
+		case hasDefault:
+			base, baseTier = d, "default"
+		}
+
+		// Redundant prefix rules: every overlapping, more general rule has
+		// the same status and so does the level below them.
+		for _, r := range prefs {
+			if alt, tier, ok := l.prefixAlternative(r, prefs, base, baseTier); ok && alt == r.val {
+				l.add(SeverityWarning, CheckRedundant, c, fmt.Sprintf("%s prefix %q", name, r.pattern),
+					"same status (%s) as the %s it refines", format(r.val), tier)
+			}
+		}
+
+		// Redundant exact rules: the prefix rules (or the code-level status)
+		// already give the reason the same status.
+		t := segmenttrie.NewWithGrammar[int](g)
+		for _, r := range prefs {
+			_ = t.Insert(r.pattern, r.val)
+		}
+		for _, r := range exacts {
+			alt, tier := base, baseTier
+			if v, ok, pat := t.MatchWithPattern(r.pattern); ok {
+				alt, tier = v, fmt.Sprintf("prefix %q", pat)
+			}
+			if alt == r.val {
+				l.add(SeverityWarning, CheckRedundant, c, fmt.Sprintf("%s exact %q", name, r.pattern),
+					"same status (%s) as the %s", format(r.val), tier)
+			}
+		}
+
+		// Redundant override: with PrefixFirst it only replaces the default.
+		if d, ok := defaults[c]; ok && hasOverride && ov == d {
+			l.add(SeverityWarning, CheckRedundant, c, name+" override",
+				"same status (%s) as the default", format(ov))
+		}
+	}
+}
+
+// effective validates rules, reports invalid and duplicate ones and returns
+// the remaining rules in declaration order, with the last value winning.
+func (l *linter) effective(kind string, c code.Code, rules []prefixRule, format func(int) string,
+	normalize func(string) (string, error)) []lintRule {
+	var out []lintRule
+	at := make(map[string]int)
+	for _, r := range rules {
+		p, err := normalize(r.prefix)
+		if err != nil {
+			l.add(SeverityError, CheckInvalid, c, fmt.Sprintf("%s %q", kind, r.prefix), "%v", err)
+			continue
+		}
+		i, seen := at[p]
+		if !seen {
+			at[p] = len(out)
+			out = append(out, lintRule{pattern: p, val: r.val})
+			continue
+		}
+		rule := fmt.Sprintf("%s %q", kind, p)
+		if out[i].val == r.val {
+			l.add(SeverityWarning, CheckDuplicate, c, rule, "registered twice with the same status (%s)", format(r.val))
+		} else {
+			l.add(SeverityError, CheckDuplicate, c, rule, "registered twice; %s replaces %s",
+				format(r.val), format(out[i].val))
+		}
+		out[i].val = r.val
+	}
+	return out
+}
+
+// prefixAlternative returns the status reasons under r would get without r,
+// when that status is unambiguous: all other prefix rules that overlap r at
+// most as deep as r must agree and, unless one of them covers r entirely,
+// agree with base too.
+func (l *linter) prefixAlternative(r lintRule, prefs []lintRule, base int, baseTier string) (int, string, bool) {
+	segs := strings.Split(r.pattern, ".")
+	alt, tier, covered := 0, "", false
+	first, depth := true, 0
+	for _, o := range prefs {
+		if o.pattern == r.pattern {
+			continue
+		}
+		osegs := strings.Split(o.pattern, ".")
+		if len(osegs) > len(segs) {
+			continue
+		}
+		overlap, covers := true, true
+		for i, s := range osegs {
+			switch {
+			case s == segs[i] || s == "*":
+			case segs[i] == "*":
+				covers = false
+			default:
+				overlap, covers = false, false
+			}
+		}
+		if !overlap {
+			continue
+		}
+		if !first && o.val != alt {
+			return 0, "", false
+		}
+		if first || len(osegs) > depth {
+			// Name the closest rule in messages.
+			alt, tier, depth = o.val, fmt.Sprintf("prefix %q", o.pattern), len(osegs)
+		}
+		first = false
+		covered = covered || covers
+	}
+	switch {
+	case first:
+		return base, baseTier, true
+	case !covered && base != alt:
+		return 0, "", false
+	}
+	return alt, tier, true
+}
+
+// consistency reports codes and reason rules whose resolved HTTP and gRPC
+// statuses are not Compatible: an error when they belong to different
+// classes (see compatibleClass), a warning otherwise. Only codes with user
+// rules are checked; the library defaults are curated as a whole.
+func (l *linter) consistency(m *mapper) {
+	var cs []code.Code
+	seen := make(map[code.Code]bool)
+	for c, v := range l.b.httpDefaults {
+		if d, ok := defaultHTTP[c]; !seen[c] && (!ok || d != v) {
+			seen[c] = true
+			cs = append(cs, c)
+		}
+	}
+	for c, v := range l.b.grpcDefaults {
+		if d, ok := defaultGRPC[c]; !seen[c] && (!ok || int(d) != v) {
+			seen[c] = true
+			cs = append(cs, c)
+		}
+	}
+	for _, src := range []map[code.Code]int{l.b.httpOverride, l.b.grpcOverride} {
+		for c := range src {
+			if !seen[c] {
+				seen[c] = true
+				cs = append(cs, c)
+			}
+		}
+	}
+	for c := range l.patterns {
+		if !seen[c] {
+			seen[c] = true
+			cs = append(cs, c)
+		}
+	}
+	slices.Sort(cs)
+
+	for _, c := range cs {
+		type pair struct {
+			http int
+			grpc codes.Code
+		}
+		reported := make(map[pair]bool)
+		check := func(rule string, r reason.Reason) {
+			h, _ := m.resolveHTTP(c, r)
+			g, _ := m.resolveGRPC(c, r)
+			if Compatible(h, g) || reported[pair{h, g}] {
+				return
+			}
+			reported[pair{h, g}] = true
+			sev := SeverityWarning
+			if !compatibleClass(h, g) {
+				sev = SeverityError
+			}
+			l.add(sev, CheckInconsistent, c, rule, "HTTP %d does not match gRPC %s (canonical HTTP %d)",
+				h, grpcCodeName(g), CanonicalHTTP(g))
+		}
+
+		check("", reason.Empty)
+		for _, p := range l.patterns[c] {
+			r, ok := sampleReason(l.b.grammar, p)
+			if !ok {
+				continue
+			}
+			check(fmt.Sprintf("reason %q", p), r)
+		}
+	}
+}
+
+// compatibleClass reports whether an HTTP status and a gRPC code agree on
+// who is at fault: success, a client error (4xx) or a server error (5xx).
+func compatibleClass(http int, grpc codes.Code) bool {
+	switch grpc {
+	case codes.OK:
+		return http >= 200 && http < 300
+	case codes.Unknown, codes.Internal, codes.DataLoss, codes.Unavailable,
+		codes.DeadlineExceeded, codes.Unimplemented:
+		return http >= 500 && http < 600
+	default:
+		return http >= 400 && http < 500
+	}
+}
@@ -20,13 +20,15 @@ import (
 	"fmt"
 	"strings"
 
-	"dirpx.dev/derrors/apis"
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/mapper/internal/segmenttrie"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/mapper/internal/segmenttrie"
+	"dirpx.dev/derrors/v2/reason"
 	"google.golang.org/grpc/codes"
 )
 
+var _ apis.Explainer = (*mapper)(nil)
+
 // New constructs an immutable apis.Mapper snapshot.
 //
 // The resulting apis.Mapper is fully thread-safe and designed for long-lived reuse.
@@ -36,15 +38,34 @@ import (
 // Build process overview:
 //
 //  1. Seed the builder with library defaults (HTTP & gRPC).
-//  2. Apply user-provided options (defaults, overrides, prefix rules).
-//  3. Normalize and validate all reason prefixes (via reason.Normalize/Parse).
+//  2. Apply user-provided options (defaults, overrides, exact and prefix rules).
+//  3. Normalize and validate all exact reasons and reason prefixes against
+//     the configured reason.Grammar (see WithGrammar).
 //  4. Build per-code segment tries (HTTP & gRPC) supporting longest-prefix-match
 //     with '*' as a single-segment wildcard.
 //  5. Freeze all maps and tries into immutable copies (fresh allocations).
+//  6. Build the inverse indexes used by FromHTTP / FromGRPC.
 //
 // Errors returned from this function indicate invalid prefixes or configuration
 // issues during normalization or trie construction.
+//
+// With WithStrict, New additionally runs Lint and fails on error findings.
 func New(opts ...Option) (apis.Mapper, error) {
+	b := newSeededBuilder(opts)
+	m, err := build(b)
+	if err != nil {
+		return nil, err
+	}
+	if b.strict {
+		if err := lintError(lint(b, m)); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// newSeededBuilder runs steps (1) and (2) of New.
+func newSeededBuilder(opts []Option) *builder {
 	// (0) Start with an empty builder.
 	// We do not assume any pre-seeded state.
 	b := newBuilder()
@@ -64,6 +85,33 @@ func New(opts ...Option) (apis.Mapper, error) {
 	for _, opt := range opts {
 		opt(b)
 	}
+	return b
+}
+
+// build runs steps (3) to (6) of New on a seeded builder.
+func build(b *builder) (*mapper, error) {
+	// (2a) Reject settings the lookups cannot honor: an unknown precedence
+	// would silently skip every override, and the fallbacks are returned
+	// as-is. The bounds match the ones FromConfig checks.
+	if !b.precedence.valid() {
+		return nil, fmt.Errorf("mapper: unknown precedence %d", b.precedence)
+	}
+	if b.fallbackHTTP < 100 || b.fallbackHTTP > 599 {
+		return nil, fmt.Errorf("mapper: invalid HTTP fallback %d", b.fallbackHTTP)
+	}
+	if int(b.fallbackGRPC) >= len(grpcNames) {
+		return nil, fmt.Errorf("mapper: unknown gRPC fallback %d", b.fallbackGRPC)
+	}
+
+	// (2b) Validate exact-reason rules into per-code hash maps.
+	httpExact, err := buildExact(b.grammar, "HTTP", b.httpExact, func(v int) int { return v })
+	if err != nil {
+		return nil, err
+	}
+	grpcExact, err := buildExact(b.grammar, "gRPC", b.grpcExact, func(v int) codes.Code { return codes.Code(v) })
+	if err != nil {
+		return nil, err
+	}
 
 	// (3) Build per-code HTTP prefix tries.
 	// Each rule prefix is normalized and validated before insertion.
@@ -72,9 +120,9 @@ func New(opts ...Option) (apis.Mapper, error) {
 		if len(rules) == 0 {
 			continue
 		}
-		t := segmenttrie.New[int]()
+		t := segmenttrie.NewWithGrammar[int](b.grammar)
 		for _, r := range rules {
-			p, err := normalizeAndValidatePrefix(r.prefix)
+			p, err := normalizeAndValidatePrefix(b.grammar, r.prefix)
 			if err != nil {
 				return nil, fmt.Errorf("mapper: invalid HTTP reason-prefix %q for code %q: %w", r.prefix, c, err)
 			}
@@ -92,9 +140,9 @@ func New(opts ...Option) (apis.Mapper, error) {
 		if len(rules) == 0 {
 			continue
 		}
-		t := segmenttrie.New[codes.Code]()
+		t := segmenttrie.NewWithGrammar[codes.Code](b.grammar)
 		for _, r := range rules {
-			p, err := normalizeAndValidatePrefix(r.prefix)
+			p, err := normalizeAndValidatePrefix(b.grammar, r.prefix)
 			if err != nil {
 				return nil, fmt.Errorf("mapper: invalid gRPC reason-prefix %q for code %q: %w", r.prefix, c, err)
 			}
@@ -112,13 +160,22 @@ func New(opts ...Option) (apis.Mapper, error) {
 		grpcDefault:  freezeGRPCDefaults(b.grpcDefaults),
 		httpOverride: freezeHTTPOverrides(b.httpOverride),
 		grpcOverride: freezeGRPCOverrides(b.grpcOverride),
+		httpExact:    httpExact,
+		grpcExact:    grpcExact,
 		httpTrie:     freezeHTTPTrie(httpTrie),
 		grpcTrie:     freezeGRPCTrie(grpcTrie),
 
 		fallbackHTTP: b.fallbackHTTP,
 		fallbackGRPC: b.fallbackGRPC,
+		precedence:   b.precedence,
+		onFallback:   b.onFallback,
+		grammar:      b.grammar,
 	}
 
+	// (6) Invert the code-level rules for FromHTTP / FromGRPC.
+	m.httpReverse = buildReverse(m.httpDefault, m.httpOverride, b.httpReversePref)
+	m.grpcReverse = buildReverse(m.grpcDefault, m.grpcOverride, b.grpcReversePref)
+
 	return m, nil
 }
 
@@ -135,12 +192,18 @@ type mapper struct {
 	grpcDefault map[code.Code]codes.Code
 
 	// httpOverride holds explicit HTTP statuses for specific codes.
-	// These take precedence over defaults but are below per-reason LPM rules.
+	// These take precedence over defaults; their rank against per-reason
+	// rules depends on precedence.
 	httpOverride map[code.Code]int
 
 	// grpcOverride holds explicit gRPC statuses for specific codes.
 	grpcOverride map[code.Code]codes.Code
 
+	// httpExact and grpcExact hold per-code exact-reason rules. They are
+	// checked before the tries with a plain map lookup.
+	httpExact map[code.Code]map[reason.Reason]int
+	grpcExact map[code.Code]map[reason.Reason]codes.Code
+
 	// httpTrie stores per-code tries that resolve HTTP statuses based on
 	// reason prefixes (dot-separated, with "*" for one-segment wildcards).
 	httpTrie map[code.Code]*segmenttrie.Trie[int]
@@ -149,218 +212,325 @@ type mapper struct {
 	// reason prefixes.
 	grpcTrie map[code.Code]*segmenttrie.Trie[codes.Code]
 
-	// fallbackHTTP is used when there is no mapper at all for a code.
-	// Typically http.StatusInternalServerError.
+	// fallbackHTTP is used when there is no rule at all for a code.
+	// http.StatusInternalServerError unless set with WithHTTPFallback.
 	fallbackHTTP int
 
-	// fallbackGRPC is used when there is no mapper at all for a code.
-	// Typically codes.Internal.
+	// fallbackGRPC is used when there is no rule at all for a code.
+	// codes.Internal unless set with WithGRPCFallback.
 	fallbackGRPC codes.Code
+
+	// onFallback, if set, is told about every resolution that ended in a
+	// fallback; see OnFallback.
+	onFallback func(code.Code, reason.Reason)
+
+	// precedence ranks overrides against exact and prefix rules.
+	precedence Precedence
+
+	// grammar is the reason grammar the rules were validated with.
+	grammar reason.Grammar
+
+	// httpReverse and grpcReverse are the inverse indexes used by FromHTTP
+	// and FromGRPC; see buildReverse.
+	httpReverse map[int]code.Code
+	grpcReverse map[codes.Code]code.Code
 }
 
 // HTTPStatus resolves an HTTP status for the given code and reason.
 //
-// Resolution order (highest to lowest):
-//  1. exact per-code override (explicitly registered);
+// Resolution order (highest to lowest) with the default PrefixFirst:
+//  1. per-code exact-reason rule;
 //  2. per-code longest-prefix-match rule on the reason;
-//  3. per-code default (library or user overridden);
-//  4. hardcoded ultimate fallback (500).
+//  3. per-code override (explicitly registered);
+//  4. per-code default (library or user overridden);
+//  5. global fallback (WithHTTPFallback, 500 unless set).
+//
+// With OverrideFirst the override moves to the top.
 //
 // The reason is treated as a dot-separated string; LPM rules are stored per code.
 func (m *mapper) HTTPStatus(c code.Code, r reason.Reason) int {
-	// 1. Fast path: exact override for this code.
-	if v, ok := m.httpOverride[c]; ok {
-		return v
+	v, ok := m.resolveHTTP(c, r)
+	if !ok {
+		m.fellBack(c, r)
+	}
+	return v
+}
+
+// resolveHTTP implements HTTPStatus; ok is false when the fallback was used.
+func (m *mapper) resolveHTTP(c code.Code, r reason.Reason) (int, bool) {
+	// 0. Override first, when configured so.
+	if m.precedence == OverrideFirst {
+		if v, ok := m.httpOverride[c]; ok {
+			return v, true
+		}
+	}
+
+	// 1. Exact reason rule: a plain map lookup, no allocation.
+	if v, ok := m.httpExact[c][r]; ok {
+		return v, true
 	}
 
 	// 2. Per-code prefix LPM over the reason.
 	if idx, ok := m.httpTrie[c]; ok && idx != nil {
 		if v, ok := idx.Match(string(r)); ok {
-			return v
+			return v, true
 		}
 	}
 
-	// 3. Per-code default.
+	// 3. Per-code override (PrefixFirst).
+	if m.precedence == PrefixFirst {
+		if v, ok := m.httpOverride[c]; ok {
+			return v, true
+		}
+	}
+
+	// 4. Per-code default.
 	if v, ok := m.httpDefault[c]; ok {
-		return v
+		return v, true
 	}
 
-	// 4. Ultimate fallback: HTTP must never be zero.
-	return 500
+	// 5. Global fallback (WithHTTPFallback): HTTP must never be zero.
+	return m.fallbackHTTP, false
 }
 
 // GRPCStatus resolves a gRPC status for the given code and reason.
 // Uses the same precedence as HTTPStatus, but returns gRPC codes.
 //
-// Resolution order:
-//  1. exact per-code override;
+// Resolution order with the default PrefixFirst:
+//  1. per-code exact-reason rule;
 //  2. per-code LPM by reason;
-//  3. per-code default;
-//  4. hardcoded fallback (codes.Internal).
+//  3. per-code override;
+//  4. per-code default;
+//  5. global fallback (WithGRPCFallback, codes.Internal unless set).
+//
+// With OverrideFirst the override moves to the top.
 func (m *mapper) GRPCStatus(c code.Code, r reason.Reason) codes.Code {
-	// 1. Exact override.
-	if v, ok := m.grpcOverride[c]; ok {
-		return v
+	v, ok := m.resolveGRPC(c, r)
+	if !ok {
+		m.fellBack(c, r)
+	}
+	return v
+}
+
+// resolveGRPC implements GRPCStatus; ok is false when the fallback was used.
+func (m *mapper) resolveGRPC(c code.Code, r reason.Reason) (codes.Code, bool) {
+	// 0. Override first, when configured so.
+	if m.precedence == OverrideFirst {
+		if v, ok := m.grpcOverride[c]; ok {
+			return v, true
+		}
+	}
+
+	// 1. Exact reason rule.
+	if v, ok := m.grpcExact[c][r]; ok {
+		return v, true
 	}
 
 	// 2. Trie-based LPM for this code.
 	if idx, ok := m.grpcTrie[c]; ok && idx != nil {
 		if v, ok := idx.Match(string(r)); ok {
-			return v
+			return v, true
 		}
 	}
 
-	// 3. Default for this code.
+	// 3. Per-code override (PrefixFirst).
+	if m.precedence == PrefixFirst {
+		if v, ok := m.grpcOverride[c]; ok {
+			return v, true
+		}
+	}
+
+	// 4. Default for this code.
 	if v, ok := m.grpcDefault[c]; ok {
-		return v
+		return v, true
 	}
 
-	// 4. Ultimate fallback.
-	return codes.Internal
+	// 5. Global fallback (WithGRPCFallback).
+	return m.fallbackGRPC, false
 }
 
 // Status resolves both HTTP and gRPC using the same inputs.
 // This keeps HTTP/GRPC decisions consistent for a single logical error.
+// The OnFallback hook runs at most once per call.
 func (m *mapper) Status(c code.Code, r reason.Reason) apis.Status {
-	return apis.Status{
-		HTTP: m.HTTPStatus(c, r),
-		GRPC: m.GRPCStatus(c, r),
+	h, okH := m.resolveHTTP(c, r)
+	g, okG := m.resolveGRPC(c, r)
+	if !okH || !okG {
+		m.fellBack(c, r)
+	}
+	return apis.Status{HTTP: h, GRPC: g}
+}
+
+// fellBack reports a fallback resolution to the OnFallback hook, if any.
+func (m *mapper) fellBack(c code.Code, r reason.Reason) {
+	if m.onFallback != nil {
+		m.onFallback(c, r)
 	}
 }
 
 // Explain produces a textual trace of how the mapper resolved HTTP and gRPC
-// statuses for a particular (code, reason) pair.
+// statuses for a particular (code, reason) pair. It renders Resolve.
 //
 // This is primarily a diagnostic tool: it shows which tier matched
-// (override, prefix, default, or fallback) and, for prefix matches,
-// which pattern was used.
+// (override, exact, prefix, default, or fallback) and, for prefix matches,
+// which pattern was used. Use Resolve when the decision is needed as data.
 //
 // Example output:
 //
-//	code="unavailable" reason="storage.pg.connect_timeout"
+//	code="unavailable" reason="storage.pg.connect_timeout" precedence=prefix-first
 //	http:  source=prefix pattern="storage.pg" -> 503
 //	grpc:  source=default -> UNAVAILABLE(14)
 //
 // Notes:
-//   - source ∈ {override | prefix | default | fallback}
+//   - source ∈ {override | exact | prefix | default | fallback}
 //   - pattern is the rule as it was stored in the trie (may contain "*")
 func (m *mapper) Explain(c code.Code, r reason.Reason) string {
-	var b strings.Builder
-	_, _ = fmt.Fprintf(&b, "code=%q reason=%q\n", c, r)
-
-	// ---- HTTP ----
-	switch src, httpLine := m.explainHTTP(c, r); src {
-	case "override", "prefix", "default", "fallback":
-		_, _ = fmt.Fprintln(&b, httpLine)
-	default:
-		// Defensive: unexpected source.
-		_, _ = fmt.Fprintln(&b, "http:  source=unknown")
-	}
+	return formatResolution(m.Resolve(c, r))
+}
 
-	// ---- gRPC ----
-	switch src, grpcLine := m.explainGRPC(c, r); src {
-	case "override", "prefix", "default", "fallback":
-		_, _ = fmt.Fprintln(&b, grpcLine)
-	default:
-		_, _ = fmt.Fprintln(&b, "grpc:  source=unknown")
+// Resolve implements apis.Explainer. For each transport it walks the same
+// tiers as HTTPStatus and GRPCStatus, in the configured precedence, and
+// collects every rule that matches; the first one wins and the others are
+// reported as lost. Unlike the lookups it allocates and never calls the
+// OnFallback hook.
+func (m *mapper) Resolve(c code.Code, r reason.Reason) apis.Resolution {
+	return apis.Resolution{
+		Code:       c,
+		Reason:     r,
+		Precedence: m.precedence.String(),
+		HTTP: tiers[int]{
+			override: m.httpOverride,
+			exact:    m.httpExact,
+			trie:     m.httpTrie,
+			def:      m.httpDefault,
+			fallback: m.fallbackHTTP,
+		}.decide(m.precedence, c, r),
+		GRPC: tiers[codes.Code]{
+			override: m.grpcOverride,
+			exact:    m.grpcExact,
+			trie:     m.grpcTrie,
+			def:      m.grpcDefault,
+			fallback: m.fallbackGRPC,
+		}.decide(m.precedence, c, r),
 	}
-
-	return strings.TrimSuffix(b.String(), "\n")
 }
 
-// explainHTTP returns the origin ("override", "prefix", "default", "fallback")
-// and a formatted line describing how the HTTP status was chosen.
-func (m *mapper) explainHTTP(c code.Code, r reason.Reason) (source, line string) {
-	// 1) exact per-code override
-	if v, ok := m.httpOverride[c]; ok {
-		return "override", fmt.Sprintf("http: source=override -> %d", v)
-	}
+// tiers bundles the rules of one transport for Resolve.
+type tiers[S any] struct {
+	override map[code.Code]S
+	exact    map[code.Code]map[reason.Reason]S
+	trie     map[code.Code]*segmenttrie.Trie[S]
+	def      map[code.Code]S
+	fallback S
+}
 
-	// 2) per-code LPM against the reason
-	if idx, ok := m.httpTrie[c]; ok && idx != nil {
-		if v, ok2, pat := idx.MatchWithPattern(string(r)); ok2 {
-			return "prefix", fmt.Sprintf("http: source=prefix pattern=%q -> %d", pat, v)
+// decide lists the matching rules in resolution order and splits them into
+// the winner and the losers. The fallback only appears when nothing matched.
+func (t tiers[S]) decide(p Precedence, c code.Code, r reason.Reason) apis.Decision[S] {
+	var cands []apis.Candidate[S]
+	override := func() {
+		if v, ok := t.override[c]; ok {
+			cands = append(cands, apis.Candidate[S]{Tier: apis.TierOverride, Value: v})
 		}
 	}
 
-	// 3) per-code default
-	if v, ok := m.httpDefault[c]; ok {
-		return "default", fmt.Sprintf("http: source=default -> %d", v)
+	if p == OverrideFirst {
+		override()
+	}
+	if v, ok := t.exact[c][r]; ok {
+		cands = append(cands, apis.Candidate[S]{Tier: apis.TierExact, Pattern: string(r), Value: v})
+	}
+	for _, h := range t.trie[c].MatchAll(string(r)) {
+		cands = append(cands, apis.Candidate[S]{Tier: apis.TierPrefix, Pattern: h.Pattern, Value: h.Value})
+	}
+	if p == PrefixFirst {
+		override()
+	}
+	if v, ok := t.def[c]; ok {
+		cands = append(cands, apis.Candidate[S]{Tier: apis.TierDefault, Value: v})
 	}
 
-	// 4) global fallback
-	return "fallback", fmt.Sprintf("http source=fallback -> %d", m.fallbackHTTP)
+	if len(cands) == 0 {
+		return apis.Decision[S]{Tier: apis.TierFallback, Value: t.fallback}
+	}
+	win := cands[0]
+	d := apis.Decision[S]{Tier: win.Tier, Pattern: win.Pattern, Value: win.Value}
+	if len(cands) > 1 {
+		d.Lost = cands[1:]
+	}
+	return d
 }
 
-// explainGRPC returns the origin ("override", "prefix", "default", "fallback")
-// and a formatted line describing how the gRPC status was chosen.
-func (m *mapper) explainGRPC(c code.Code, r reason.Reason) (source, line string) {
-	// 1) exact per-code override
-	if v, ok := m.grpcOverride[c]; ok {
-		return "override", fmt.Sprintf("grpc: source=override -> %s(%d)", strings.ToUpper(v.String()), int(v))
-	}
+// formatResolution renders res in the Explain format. Lost candidates are
+// not printed; they are available through Resolve.
+func formatResolution(res apis.Resolution) string {
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "code=%q reason=%q precedence=%s\n", res.Code, res.Reason, res.Precedence)
 
-	// 2) per-code LPM against the reason
-	if idx, ok := m.grpcTrie[c]; ok && idx != nil {
-		if v, ok2, pat := idx.MatchWithPattern(string(r)); ok2 {
-			return "prefix", fmt.Sprintf("grpc: source=prefix pattern=%q -> %s(%d)", pat, strings.ToUpper(v.String()), int(v))
-		}
-	}
+	// ---- HTTP ----
+	_, _ = fmt.Fprintf(&b, "http: %s -> %d\n", formatSource(res.HTTP.Tier, res.HTTP.Pattern), res.HTTP.Value)
 
-	// 3) per-code default
-	if v, ok := m.grpcDefault[c]; ok {
-		return "default", fmt.Sprintf("grpc: source=default -> %s(%d)", strings.ToUpper(v.String()), int(v))
-	}
+	// ---- gRPC ----
+	v := res.GRPC.Value
+	_, _ = fmt.Fprintf(&b, "grpc: %s -> %s(%d)", formatSource(res.GRPC.Tier, res.GRPC.Pattern), strings.ToUpper(v.String()), int(v))
 
-	// 4) global fallback
-	return "fallback", fmt.Sprintf("grpc: source=fallback -> %s(%d)", strings.ToUpper(m.fallbackGRPC.String()), int(m.fallbackGRPC))
+	return b.String()
 }
 
-// normalizeAndValidatePrefix ensures a reason prefix is canonical and valid.
-// It forbids empty strings as prefixes and delegates structural checks to reason.Parse.
-func normalizeAndValidatePrefix(raw string) (string, error) {
-	p := reason.Normalize(raw)
-	if p == "" {
-		return "", fmt.Errorf("empty prefix")
-	}
-	segs := strings.Split(p, ".")
-	allWild := true
-	for _, seg := range segs {
-		if !validPrefixSegment(seg) { // allows "*" or [a-z][a-z0-9_]*
-			return "", fmt.Errorf("invalid segment %q", seg)
-		}
-		if seg != "*" {
-			allWild = false
-		}
+// formatSource renders the "source=... [pattern=...]" part of an Explain line.
+func formatSource(tier apis.Tier, pattern string) string {
+	if tier == "" {
+		return "source=unknown"
 	}
-	if allWild {
-		return "", fmt.Errorf("prefix cannot consist of '*' only")
+	if tier == apis.TierPrefix {
+		return fmt.Sprintf("source=%s pattern=%q", tier, pattern)
 	}
-	return p, nil
+	return "source=" + string(tier)
 }
 
-// validPrefixSegment reports whether seg is a valid trie segment for prefixes.
-// Rules:
-//   - empty segments are invalid;
-//   - the segment "*" is allowed;
-//   - otherwise the segment must match: [a-z][a-z0-9_]*
-func validPrefixSegment(seg string) bool {
-	if seg == "" {
-		return false
-	}
-	if seg == "*" {
-		return true
-	}
-	// [a-z][a-z0-9_]*
-	if seg[0] < 'a' || seg[0] > 'z' {
-		return false
-	}
-	for i := 1; i < len(seg); i++ {
-		c := seg[i]
-		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
+// buildExact validates exact-reason rules under g and indexes them per code,
+// converting the builder's int values with conv. Later rules for the same
+// reason replace earlier ones.
+func buildExact[S any](g reason.Grammar, transport string, rules map[code.Code][]prefixRule, conv func(int) S) (map[code.Code]map[reason.Reason]S, error) {
+	var out map[code.Code]map[reason.Reason]S
+	for c, rs := range rules {
+		if len(rs) == 0 {
 			continue
 		}
-		return false
+		byReason := make(map[reason.Reason]S, len(rs))
+		for _, rule := range rs {
+			r, err := g.Parse(rule.prefix)
+			if err == nil && r == reason.Empty {
+				err = fmt.Errorf("reason is empty")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("mapper: invalid %s exact reason %q for code %q: %w", transport, rule.prefix, c, err)
+			}
+			byReason[r] = conv(rule.val)
+		}
+		if out == nil {
+			out = make(map[code.Code]map[reason.Reason]S, len(rules))
+		}
+		out[c] = byReason
+	}
+	return out, nil
+}
+
+// normalizeAndValidatePrefix ensures a reason prefix is canonical and valid
+// under g. Prefixes share the reason.Pattern syntax, except that "**" is implicit
+// (every rule already matches the reasons below it) and that a prefix needs
+// at least one literal segment.
+func normalizeAndValidatePrefix(g reason.Grammar, raw string) (string, error) {
+	p, err := g.ParsePattern(raw)
+	if err != nil {
+		return "", err
+	}
+	sp := p.Specificity()
+	if sp.Open {
+		return "", fmt.Errorf("'**' is implicit in prefix rules")
+	}
+	if sp.Literals == 0 {
+		return "", fmt.Errorf("prefix cannot consist of '*' only")
 	}
-	return true
+	return p.String(), nil
 }
@@ -1,13 +1,20 @@
 package mapper
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
-	"dirpx.dev/derrors/apis"
-	"dirpx.dev/derrors/code"
-	"dirpx.dev/derrors/reason"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
 	"google.golang.org/grpc/codes"
 )
 
@@ -32,6 +39,7 @@ func TestDefaults_HTTP_GRPC(t *testing.T) {
 
 func TestPriority_OverrideOverPrefixOverDefault_HTTP(t *testing.T) {
 	m, err := New(
+		WithPrecedence(OverrideFirst),
 		WithHTTPDefault(code.Unavailable, 503),              // default
 		WithHTTPPrefix(code.Unavailable, "storage.pg", 599), // prefix
 		WithHTTPOverride(code.Unavailable, 418),             // override
@@ -47,6 +55,7 @@ func TestPriority_OverrideOverPrefixOverDefault_HTTP(t *testing.T) {
 
 func TestPriority_OverrideOverPrefixOverDefault_GRPC(t *testing.T) {
 	m, err := New(
+		WithPrecedence(OverrideFirst),
 		WithGRPCDefault(code.Unavailable, int(codes.Unavailable)),
 		WithGRPCPrefix(code.Unavailable, "storage.pg", int(codes.Internal)),
 		WithGRPCOverride(code.Unavailable, int(codes.Aborted)),
@@ -60,6 +69,38 @@ func TestPriority_OverrideOverPrefixOverDefault_GRPC(t *testing.T) {
 	}
 }
 
+func TestPriority_PrefixFirstIsDefault(t *testing.T) {
+	m, err := New(
+		WithHTTPPrefix(code.Unavailable, "storage.pg", 599),
+		WithGRPCExact(code.Unavailable, "storage.pg.connect", int(codes.Internal)),
+		WithHTTPOverride(code.Unavailable, 418),
+		WithGRPCOverride(code.Unavailable, int(codes.Aborted)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	st := m.Status(code.Unavailable, mustReason("storage.pg.connect"))
+	if st.HTTP != 599 || st.GRPC != codes.Internal {
+		t.Fatalf("reason rules must win; got %+v", st)
+	}
+	st = m.Status(code.Unavailable, mustReason("cache.miss"))
+	if st.HTTP != 418 || st.GRPC != codes.Aborted {
+		t.Fatalf("override must beat the default; got %+v", st)
+	}
+}
+
+func TestPrecedence_UnknownRejected(t *testing.T) {
+	if _, err := New(WithPrecedence(Precedence(7))); err == nil || !strings.Contains(err.Error(), "unknown precedence") {
+		t.Fatalf("New must reject an unknown precedence, got %v", err)
+	}
+	if c := ConfigOf(WithPrecedence(Precedence(7))); c.Precedence != "" {
+		t.Fatalf("ConfigOf wrote precedence %q", c.Precedence)
+	}
+	if c := ConfigOf(WithPrecedence(OverrideFirst)); c.Precedence != "override-first" {
+		t.Fatalf("ConfigOf precedence = %q", c.Precedence)
+	}
+}
+
 func TestPrefix_LPM_And_SegmentBoundary(t *testing.T) {
 	m, err := New(
 		WithHTTPPrefix(code.Unavailable, "storage.pg", 503),
@@ -243,3 +284,587 @@ func BenchmarkMapperStatus_Fallback(t *testing.B) {
 func TestMapper_InterfaceSatisfaction(t *testing.T) {
 	var _ apis.Mapper = (*mapper)(nil)
 }
+
+func TestReverse_DefaultsAndPreference(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	rm := m.(apis.ReverseMapper)
+
+	httpCases := map[int]code.Code{
+		400: code.Invalid,     // invalid, missing, unsupported, expired, ...
+		404: code.NotFound,    // unique
+		409: code.Conflict,    // already_exists, conflict, stale_version
+		429: code.RateLimited, // throttled, rate_limited, quota_exceeded
+		503: code.Unavailable, // unavailable, not_ready, draining, overloaded
+		418: code.Invalid,     // unmapped 4xx
+		599: code.Internal,    // unmapped 5xx
+		200: code.Empty,
+	}
+	for st, want := range httpCases {
+		if got := rm.FromHTTP(st); got != want {
+			t.Errorf("FromHTTP(%d) = %q, want %q", st, got, want)
+		}
+	}
+	grpcCases := map[codes.Code]code.Code{
+		codes.FailedPrecondition: code.PreconditionFailed,
+		codes.Aborted:            code.Conflict,
+		codes.ResourceExhausted:  code.RateLimited,
+		codes.OutOfRange:         code.Internal,
+		codes.OK:                 code.Empty,
+	}
+	for gc, want := range grpcCases {
+		if got := rm.FromGRPC(gc); got != want {
+			t.Errorf("FromGRPC(%v) = %q, want %q", gc, got, want)
+		}
+	}
+
+	m, err = New(
+		WithHTTPReversePreference(code.Overloaded),
+		WithGRPCReversePreference(code.Overloaded),
+		WithHTTPOverride(code.Canceled, 499),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	rm = m.(apis.ReverseMapper)
+	if got := rm.FromHTTP(503); got != code.Overloaded {
+		t.Errorf("preferred FromHTTP(503) = %q", got)
+	}
+	if got := rm.FromGRPC(codes.Unavailable); got != code.Overloaded {
+		t.Errorf("preferred FromGRPC(Unavailable) = %q", got)
+	}
+	if got := rm.FromHTTP(499); got != code.Canceled {
+		t.Errorf("override FromHTTP(499) = %q", got)
+	}
+	if got := rm.FromHTTP(408); got != code.Invalid {
+		t.Errorf("overridden-away FromHTTP(408) = %q, want unmapped 4xx", got)
+	}
+}
+
+func TestWithGrammar(t *testing.T) {
+	if _, err := New(WithHTTPPrefix(code.Invalid, "api.v2", 422)); err != nil {
+		t.Fatalf("default grammar: %v", err)
+	}
+	if _, err := New(WithHTTPPrefix(code.Invalid, "api.2fa", 422)); err == nil {
+		t.Fatal("default grammar must reject digit-first segments")
+	}
+
+	m, err := New(
+		WithGrammar(reason.Grammar{MaxDepth: 5, DigitFirst: true}),
+		WithHTTPPrefix(code.Invalid, "api.2fa.*.*.otp", 422),
+	)
+	if err != nil {
+		t.Fatalf("custom grammar: %v", err)
+	}
+	if got := m.HTTPStatus(code.Invalid, "api.2fa.sms.v1.otp"); got != 422 {
+		t.Fatalf("HTTPStatus = %d, want 422", got)
+	}
+}
+
+func TestFromConfig(t *testing.T) {
+	const doc = `{
+  "version": 1,
+  "fallback": {"http": 599, "grpc": "UNKNOWN"},
+  "defaults": {"canceled": {"http": 499}},
+  "overrides": {"draining": {"http": 429, "grpc": "resource_exhausted"}},
+  "prefixes": [
+    {"code": "unavailable", "prefix": "storage.pg", "http": 502, "grpc": "ABORTED"}
+  ]
+}`
+	m, err := FromConfig(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	if got := m.HTTPStatus(code.Canceled, reason.Empty); got != 499 {
+		t.Errorf("default: HTTP = %d, want 499", got)
+	}
+	if st := m.Status(code.Draining, reason.Empty); st.HTTP != 429 || st.GRPC != codes.ResourceExhausted {
+		t.Errorf("override: %+v", st)
+	}
+	if st := m.Status(code.Unavailable, "storage.pg.connect"); st.HTTP != 502 || st.GRPC != codes.Aborted {
+		t.Errorf("prefix: %+v", st)
+	}
+	if st := m.Status("no_such_code", reason.Empty); st.HTTP != 599 || st.GRPC != codes.Unknown {
+		t.Errorf("fallback: %+v", st)
+	}
+}
+
+func TestFromConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		line int
+		rule string
+		msg  string
+	}{
+		{"syntax", "{\n  \"version\": 1,\n  \"defaults\": {,}\n}", 3, "", "invalid character"},
+		{"version", `{"version": 2}`, 1, "version", "unsupported version"},
+		{"missing version", `{}`, 0, "version", "missing version"},
+		{"unknown field", "{\n  \"version\": 1,\n  \"defaults\": {\n    \"invalid\": {\"htp\": 400}\n  }\n}", 4, "defaults.invalid", "unknown field"},
+		{"type", "{\"version\": 1,\n \"prefixes\": [\n  {\"code\": \"invalid\", \"prefix\": \"a.b\", \"http\": \"x\"}]}", 3, "prefixes.0", "cannot unmarshal"},
+		{"bad code", "{\"version\": 1,\n \"overrides\": {\"Not-A-Code\": {\"http\": 400}}}", 2, "overrides.Not-A-Code", "code"},
+		{"bad prefix", "{\"version\": 1,\n \"prefixes\": [\n  {\"code\": \"invalid\", \"prefix\": \"ok.rule\", \"http\": 400},\n  {\"code\": \"invalid\", \"prefix\": \"Bad..x\", \"http\": 400}\n]}", 4, "prefixes.1", "reason-prefix"},
+		{"bad grpc", `{"version": 1, "defaults": {"invalid": {"grpc": "NOPE"}}}`, 1, "defaults.invalid", "unknown gRPC code"},
+		{"bad http", `{"version": 1, "defaults": {"invalid": {"http": 42}}}`, 1, "defaults.invalid", "invalid HTTP status"},
+		{"empty rule", `{"version": 1, "fallback": {}}`, 1, "fallback", "neither"},
+		{"trailing", `{"version": 1} {}`, 0, "", "after the document"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromConfig(strings.NewReader(tt.doc))
+			var ce *ConfigError
+			if !errors.As(err, &ce) {
+				t.Fatalf("err = %v, want *ConfigError", err)
+			}
+			if ce.Line != tt.line || ce.Rule != tt.rule || !strings.Contains(ce.Err.Error(), tt.msg) {
+				t.Fatalf("got line=%d rule=%q err=%v; want line=%d rule=%q msg~%q",
+					ce.Line, ce.Rule, ce.Err, tt.line, tt.rule, tt.msg)
+			}
+		})
+	}
+}
+
+func TestLoadFile_ErrorNamesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapper.json")
+	if err := os.WriteFile(path, []byte("{\n  \"version\": 1,\n  \"defaults\": {\"invalid\": {\"http\": 1}}\n}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadFile(path)
+	want := "mapper: " + path + ":3: defaults.invalid: invalid HTTP status 1"
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %q", err, want)
+	}
+}
+
+func TestWriteConfig_RoundTrip(t *testing.T) {
+	opts := []Option{
+		WithPrecedence(OverrideFirst),
+		WithGrammar(reason.Grammar{MaxDepth: 5}),
+		WithHTTPDefault(code.Canceled, 499),
+		WithGRPCOverride(code.Draining, int(codes.ResourceExhausted)),
+		WithHTTPExact(code.Unauthenticated, "auth.jwt", 401),
+		WithHTTPPrefix(code.Unavailable, "storage.pg", 502),
+		WithGRPCPrefix(code.Unavailable, "storage.pg", int(codes.Aborted)),
+		WithGRPCPrefix(code.Unavailable, "storage.*.auth", int(codes.PermissionDenied)),
+		WithHTTPReversePreference(code.Conflict),
+	}
+	var buf bytes.Buffer
+	if err := WriteConfig(&buf, opts...); err != nil {
+		t.Fatalf("WriteConfig: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"grpc": "RESOURCE_EXHAUSTED"`) {
+		t.Errorf("gRPC codes must be written by name:\n%s", buf.String())
+	}
+
+	cfg, err := ReadConfig(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadConfig: %v\n%s", err, buf.String())
+	}
+	if !reflect.DeepEqual(cfg, ConfigOf(opts...)) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", cfg, ConfigOf(opts...))
+	}
+	if n := len(cfg.Prefixes); n != 2 {
+		t.Fatalf("prefix rules for the same pattern must share an entry; got %d", n)
+	}
+
+	want, _ := New(opts...)
+	got, err := FromConfig(&buf)
+	if err != nil {
+		t.Fatalf("FromConfig: %v", err)
+	}
+	for _, tc := range []struct {
+		c code.Code
+		r reason.Reason
+	}{
+		{code.Canceled, ""},
+		{code.Draining, ""},
+		{code.Unavailable, "storage.pg.connect"},
+		{code.Unavailable, "storage.s3.auth.denied"},
+		{code.Unavailable, "cache.miss"},
+		{code.Unauthenticated, "auth.jwt"},
+	} {
+		if g, w := got.Status(tc.c, tc.r), want.Status(tc.c, tc.r); g != w {
+			t.Errorf("Status(%q, %q) = %+v, want %+v", tc.c, tc.r, g, w)
+		}
+	}
+}
+
+func TestNewReloadable_RejectsNilMapper(t *testing.T) {
+	r, err := NewReloadable(func() (apis.Mapper, error) { return nil, nil })
+	if err == nil || r != nil {
+		t.Fatalf("NewReloadable = %v, %v; want an error for a nil mapper", r, err)
+	}
+}
+
+func TestReloadable_KeepsLastGoodSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapper.json")
+	write := func(doc string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`{"version": 1, "overrides": {"canceled": {"http": 499}}}`)
+
+	var reported []error
+	r, err := NewReloadable(FileLoader(path), WithReloadErrorHandler(func(err error) {
+		reported = append(reported, err)
+	}))
+	if err != nil {
+		t.Fatalf("NewReloadable: %v", err)
+	}
+	if got := r.HTTPStatus(code.Canceled, reason.Empty); got != 499 {
+		t.Fatalf("initial HTTPStatus = %d, want 499", got)
+	}
+
+	write(`{"version": 1, "overrides": {"canceled": {"http": 408}}}`)
+	if err := r.Reload(); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := r.HTTPStatus(code.Canceled, reason.Empty); got != 408 {
+		t.Fatalf("reloaded HTTPStatus = %d, want 408", got)
+	}
+
+	write(`{"version": 1, "overrides": {"canceled": {"http": 4080}}}`)
+	if err := r.Reload(); err == nil {
+		t.Fatal("Reload of an invalid document must fail")
+	}
+	if len(reported) != 1 {
+		t.Fatalf("error handler calls = %d, want 1", len(reported))
+	}
+	if got := r.HTTPStatus(code.Canceled, reason.Empty); got != 408 {
+		t.Fatalf("after failed reload HTTPStatus = %d, want last good 408", got)
+	}
+	if got := r.FromHTTP(408); got != code.Canceled {
+		t.Fatalf("FromHTTP(408) = %q, want %q", got, code.Canceled)
+	}
+}
+
+func TestReloadable_Watch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mapper.json")
+	if err := os.WriteFile(path, []byte(`{"version": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewReloadable(FileLoader(path))
+	if err != nil {
+		t.Fatalf("NewReloadable: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- r.Watch(ctx, path, 5*time.Millisecond) }()
+
+	if err := os.WriteFile(path, []byte(`{"version": 1, "defaults": {"canceled": {"http": 499}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for r.HTTPStatus(code.Canceled, reason.Empty) != 499 {
+		if time.Now().After(deadline) {
+			t.Fatal("Watch did not pick up the change")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Watch returned %v, want context.Canceled", err)
+	}
+}
+
+func TestExact_BeforePrefix_NoDescendants(t *testing.T) {
+	m, err := New(
+		WithHTTPPrefix(code.Unauthenticated, "auth", 403),
+		WithHTTPExact(code.Unauthenticated, "auth.jwt", 401),
+		WithGRPCExact(code.Unauthenticated, "Auth.JWT", int(codes.PermissionDenied)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := m.HTTPStatus(code.Unauthenticated, "auth.jwt"); got != 401 {
+		t.Errorf("exact: HTTP = %d, want 401", got)
+	}
+	if got := m.HTTPStatus(code.Unauthenticated, "auth.jwt.expired"); got != 403 {
+		t.Errorf("child of exact: HTTP = %d, want prefix 403", got)
+	}
+	if got := m.GRPCStatus(code.Unauthenticated, "auth.jwt"); got != codes.PermissionDenied {
+		t.Errorf("normalized exact: gRPC = %v, want PermissionDenied", got)
+	}
+	if exp := m.Explain(code.Unauthenticated, "auth.jwt"); !strings.Contains(exp, "source=exact") {
+		t.Errorf("Explain must report source=exact:\n%s", exp)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() { _ = m.Status(code.Unauthenticated, "auth.jwt") })
+	if allocs != 0 {
+		t.Errorf("exact lookup allocates %.0f times", allocs)
+	}
+
+	for _, bad := range []string{"", "auth.*", "a"} {
+		if _, err := New(WithHTTPExact(code.Unauthenticated, bad, 401)); err == nil {
+			t.Errorf("New accepted exact reason %q", bad)
+		}
+	}
+}
+
+func TestFallback_ConfigurableAndHooked(t *testing.T) {
+	var calls []string
+	m, err := New(
+		WithHTTPFallback(599),
+		WithGRPCFallback(int(codes.Unknown)),
+		OnFallback(func(c code.Code, r reason.Reason) {
+			calls = append(calls, string(c)+":"+string(r))
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if st := m.Status("unmapped", "some.reason"); st.HTTP != 599 || st.GRPC != codes.Unknown {
+		t.Fatalf("Status = %+v, want 599/Unknown", st)
+	}
+	if len(calls) != 1 || calls[0] != "unmapped:some.reason" {
+		t.Fatalf("OnFallback calls after Status = %q, want one", calls)
+	}
+	if got := m.HTTPStatus("unmapped", ""); got != 599 {
+		t.Fatalf("HTTPStatus = %d, want 599", got)
+	}
+	if got := m.GRPCStatus("unmapped", ""); got != codes.Unknown {
+		t.Fatalf("GRPCStatus = %v, want Unknown", got)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("OnFallback calls = %d, want 3", len(calls))
+	}
+
+	exp := m.Explain("unmapped", "")
+	if !strings.Contains(exp, "http: source=fallback -> 599") || !strings.Contains(exp, "grpc: source=fallback -> UNKNOWN(2)") {
+		t.Fatalf("Explain must report the configured fallbacks:\n%s", exp)
+	}
+	_ = m.Status(code.Invalid, "")
+	if len(calls) != 3 {
+		t.Fatalf("mapped codes and Explain must not call OnFallback; calls = %d", len(calls))
+	}
+}
+
+func TestFallback_Validated(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{"http zero", WithHTTPFallback(0), "invalid HTTP fallback 0"},
+		{"http too large", WithHTTPFallback(600), "invalid HTTP fallback 600"},
+		{"grpc unknown", WithGRPCFallback(17), "unknown gRPC fallback 17"},
+		{"grpc negative", WithGRPCFallback(-1), "unknown gRPC fallback"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.opt); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("New error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve_WinnerAndLosers(t *testing.T) {
+	m, err := New(
+		WithHTTPPrefix(code.Conflict, "lock", 423),
+		WithHTTPPrefix(code.Conflict, "lock.held", 409),
+		WithHTTPExact(code.Conflict, "lock.held.by_peer", 429),
+		WithHTTPOverride(code.Conflict, 418),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	x := m.(apis.Explainer)
+
+	res := x.Resolve(code.Conflict, "lock.held.by_peer")
+	if res.Precedence != "prefix-first" || res.Status() != m.Status(code.Conflict, "lock.held.by_peer") {
+		t.Fatalf("Resolve = %+v, inconsistent with Status", res)
+	}
+	want := apis.Decision[int]{
+		Tier: apis.TierExact, Pattern: "lock.held.by_peer", Value: 429,
+		Lost: []apis.Candidate[int]{
+			{Tier: apis.TierPrefix, Pattern: "lock.held", Value: 409},
+			{Tier: apis.TierPrefix, Pattern: "lock", Value: 423},
+			{Tier: apis.TierOverride, Value: 418},
+			{Tier: apis.TierDefault, Value: 409},
+		},
+	}
+	if !reflect.DeepEqual(res.HTTP, want) {
+		t.Fatalf("HTTP decision:\n got %+v\nwant %+v", res.HTTP, want)
+	}
+	if res.GRPC.Tier != apis.TierDefault || res.GRPC.Value != codes.Aborted || res.GRPC.Lost != nil {
+		t.Fatalf("gRPC decision = %+v", res.GRPC)
+	}
+
+	if got := x.Resolve("unmapped", ""); got.HTTP.Tier != apis.TierFallback || got.HTTP.Value != 500 {
+		t.Fatalf("fallback decision = %+v", got.HTTP)
+	}
+}
+
+func TestLint(t *testing.T) {
+	if fs := Lint(); len(fs) != 0 {
+		t.Fatalf("Lint() on library defaults = %v, want none", fs)
+	}
+
+	type key struct {
+		sev   Severity
+		check string
+		code  code.Code
+		rule  string
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want key
+	}{
+		{"redundant prefix", []Option{
+			WithHTTPPrefix(code.Unavailable, "storage.*", 502),
+			WithHTTPPrefix(code.Unavailable, "storage.pg", 502),
+		}, key{SeverityWarning, CheckRedundant, code.Unavailable, `http prefix "storage.pg"`}},
+		{"prefix equal to default", []Option{
+			WithGRPCPrefix(code.Unavailable, "cache", int(codes.Unavailable)),
+		}, key{SeverityWarning, CheckRedundant, code.Unavailable, `grpc prefix "cache"`}},
+		{"redundant exact", []Option{
+			WithHTTPExact(code.Unauthenticated, "auth.jwt", 401),
+		}, key{SeverityWarning, CheckRedundant, code.Unauthenticated, `http exact "auth.jwt"`}},
+		{"duplicate, same status", []Option{
+			WithHTTPPrefix(code.Invalid, "a.b", 422),
+			WithHTTPPrefix(code.Invalid, "A.B", 422),
+		}, key{SeverityWarning, CheckDuplicate, code.Invalid, `http prefix "a.b"`}},
+		{"duplicate, different status", []Option{
+			WithGRPCPrefix(code.Invalid, "a.b", int(codes.OutOfRange)),
+			WithGRPCPrefix(code.Invalid, "a.b", int(codes.FailedPrecondition)),
+		}, key{SeverityError, CheckDuplicate, code.Invalid, `grpc prefix "a.b"`}},
+		{"dead under override-first", []Option{
+			WithPrecedence(OverrideFirst),
+			WithHTTPOverride(code.Conflict, 423),
+			WithHTTPPrefix(code.Conflict, "lock", 409),
+		}, key{SeverityError, CheckDead, code.Conflict, `http prefix "lock"`}},
+		{"inconsistent", []Option{
+			WithGRPCOverride(code.NotFound, int(codes.Internal)),
+		}, key{SeverityError, CheckInconsistent, code.NotFound, ""}},
+		{"inconsistent reason rule", []Option{
+			WithHTTPPrefix(code.Unavailable, "quota.*", 429),
+		}, key{SeverityError, CheckInconsistent, code.Unavailable, `reason "quota.*"`}},
+		{"unconventional but same class", []Option{
+			WithGRPCOverride(code.NotFound, int(codes.InvalidArgument)),
+		}, key{SeverityWarning, CheckInconsistent, code.NotFound, ""}},
+		{"invalid", []Option{
+			WithHTTPPrefix(code.Invalid, "Bad..x", 400),
+		}, key{SeverityError, CheckInvalid, code.Invalid, `http prefix "Bad..x"`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := Lint(tt.opts...)
+			for _, f := range fs {
+				if (key{f.Severity, f.Check, f.Code, f.Rule}) == tt.want {
+					return
+				}
+			}
+			t.Fatalf("Lint() = %v, want a finding %+v", fs, tt.want)
+		})
+	}
+}
+
+func TestNew_Strict(t *testing.T) {
+	redundant := []Option{
+		WithStrict(),
+		WithHTTPPrefix(code.Unavailable, "storage.*", 502),
+		WithHTTPPrefix(code.Unavailable, "storage.pg", 502),
+	}
+	if _, err := New(redundant...); err != nil {
+		t.Fatalf("strict New must accept warnings: %v", err)
+	}
+
+	dead := []Option{
+		WithPrecedence(OverrideFirst),
+		WithHTTPOverride(code.Conflict, 423),
+		WithHTTPPrefix(code.Conflict, "lock", 409),
+	}
+	if _, err := New(dead...); err != nil {
+		t.Fatalf("non-strict New: %v", err)
+	}
+	_, err := New(append(dead, WithStrict())...)
+	if !errors.Is(err, ErrLint) || !strings.Contains(err.Error(), `http prefix "lock"`) {
+		t.Fatalf("strict New error = %v, want ErrLint naming the dead rule", err)
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		http int
+		grpc codes.Code
+		want bool
+	}{
+		{204, codes.OK, true},
+		{404, codes.NotFound, true},
+		{410, codes.NotFound, true},
+		{412, codes.FailedPrecondition, true},
+		{502, codes.Unavailable, true},
+		{499, codes.Canceled, true},
+		{404, codes.Internal, false},
+		{400, codes.NotFound, false},
+		{500, codes.OK, false},
+	}
+	for _, tt := range tests {
+		if got := Compatible(tt.http, tt.grpc); got != tt.want {
+			t.Errorf("Compatible(%d, %s) = %v, want %v", tt.http, tt.grpc, got, tt.want)
+		}
+	}
+	if got := CanonicalHTTP(codes.FailedPrecondition); got != 400 {
+		t.Errorf("CanonicalHTTP(FailedPrecondition) = %d, want 400", got)
+	}
+	if got := CanonicalHTTP(codes.Code(99)); got != 500 {
+		t.Errorf("CanonicalHTTP(99) = %d, want 500", got)
+	}
+}
+
+func TestCheckConsistency(t *testing.T) {
+	def, err := New()
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	// dependency_failed is the one library default that pairs statuses of
+	// different classes (HTTP 502 with gRPC FailedPrecondition).
+	wantDefaults := []Inconsistency{{Code: code.DependencyFailed, HTTP: 502, GRPC: codes.FailedPrecondition}}
+	if got := CheckConsistency(def, nil, nil).Inconsistencies; !reflect.DeepEqual(got, wantDefaults) {
+		t.Fatalf("library defaults: %+v, want %+v", got, wantDefaults)
+	}
+
+	var fired bool
+	m, err := New(
+		WithHTTPOverride(code.NotFound, 429),
+		WithHTTPPrefix(code.Unavailable, "quota.*", 429),
+		OnFallback(func(code.Code, reason.Reason) { fired = true }),
+	)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	rep := CheckConsistency(m, []code.Code{code.NotFound, code.Unavailable, "unmapped"}, nil)
+	if fired {
+		t.Fatal("CheckConsistency must not trigger OnFallback")
+	}
+	want := []Inconsistency{
+		{Code: code.NotFound, HTTP: 429, GRPC: codes.NotFound},
+		{Code: code.Unavailable, Reason: "quota.x", Pattern: "quota.*", HTTP: 429, GRPC: codes.Unavailable},
+	}
+	if !reflect.DeepEqual(rep.Inconsistencies, want) {
+		t.Fatalf("Inconsistencies = %+v, want %+v", rep.Inconsistencies, want)
+	}
+	if rep.Checked != 4 {
+		t.Fatalf("Checked = %d, want 4", rep.Checked)
+	}
+	err = rep.Err()
+	if !errors.Is(err, ErrInconsistent) || !strings.Contains(err.Error(), `rule "quota.*": HTTP 429 with gRPC UNAVAILABLE (canonical HTTP 503)`) {
+		t.Fatalf("Err() = %v", err)
+	}
+
+	// Explicit reasons replace the mapper's own rules.
+	rl, err := NewReloadable(func() (apis.Mapper, error) { return m, nil })
+	if err != nil {
+		t.Fatalf("NewReloadable: %v", err)
+	}
+	rep = CheckConsistency(rl, []code.Code{code.Unavailable}, []reason.Reason{"quota.disk"})
+	if len(rep.Inconsistencies) != 1 || rep.Inconsistencies[0].Reason != "quota.disk" {
+		t.Fatalf("Inconsistencies = %+v, want quota.disk", rep.Inconsistencies)
+	}
+}
@@ -17,7 +17,9 @@
 package mapper
 
 import (
-	"dirpx.dev/derrors/code"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+	"google.golang.org/grpc/codes"
 )
 
 // Option configures the Mapper at build time.
@@ -40,19 +42,33 @@ func WithGRPCDefault(c code.Code, grpc int) Option {
 }
 
 // WithHTTPOverride registers an exact HTTP override for the given code.
-// Overrides take precedence over defaults but still sit below per-reason
-// prefix matches (LPM) for that code.
+// Overrides take precedence over defaults but, by default, still sit below
+// per-reason exact and prefix (LPM) rules for that code; see WithPrecedence.
 func WithHTTPOverride(c code.Code, http int) Option {
 	return func(b *builder) { b.httpOverride[c] = http }
 }
 
 // WithGRPCOverride registers an exact gRPC override for the given code.
-// Overrides take precedence over defaults but still sit below per-reason
-// prefix matches (LPM) for that code.
+// Overrides take precedence over defaults but, by default, still sit below
+// per-reason exact and prefix (LPM) rules for that code; see WithPrecedence.
 func WithGRPCOverride(c code.Code, grpc int) Option {
 	return func(b *builder) { b.grpcOverride[c] = grpc }
 }
 
+// WithHTTPExact maps exactly the given reason of code c to an HTTP status.
+// Unlike WithHTTPPrefix it does not cover the reasons below it: a rule for
+// "auth.jwt" matches "auth.jwt" but not "auth.jwt.expired". Exact rules are
+// checked before prefix rules.
+func WithHTTPExact(c code.Code, reason string, http int) Option {
+	return func(b *builder) { b.httpExact[c] = append(b.httpExact[c], prefixRule{reason, http}) }
+}
+
+// WithGRPCExact maps exactly the given reason of code c to a gRPC status.
+// See WithHTTPExact.
+func WithGRPCExact(c code.Code, reason string, grpc int) Option {
+	return func(b *builder) { b.grpcExact[c] = append(b.grpcExact[c], prefixRule{reason, grpc}) }
+}
+
 // WithHTTPPrefix adds an HTTP longest-prefix-match rule for the given code.
 // The rule is evaluated against the reason (dot-separated). A more specific
 // prefix wins. Use "*" to match a single segment.
@@ -66,3 +82,62 @@ func WithHTTPPrefix(c code.Code, prefix string, http int) Option {
 func WithGRPCPrefix(c code.Code, prefix string, grpc int) Option {
 	return func(b *builder) { b.grpcPrefixes[c] = append(b.grpcPrefixes[c], prefixRule{prefix, grpc}) }
 }
+
+// WithHTTPReversePreference sets the codes FromHTTP prefers when several
+// codes map to the same HTTP status, most preferred first. The list is
+// consulted before the library preference order; see FromHTTP.
+func WithHTTPReversePreference(cs ...code.Code) Option {
+	return func(b *builder) { b.httpReversePref = append(b.httpReversePref, cs...) }
+}
+
+// WithGRPCReversePreference sets the codes FromGRPC prefers when several
+// codes map to the same gRPC status, most preferred first. The list is
+// consulted before the library preference order; see FromGRPC.
+func WithGRPCReversePreference(cs ...code.Code) Option {
+	return func(b *builder) { b.grpcReversePref = append(b.grpcReversePref, cs...) }
+}
+
+// WithHTTPFallback sets the HTTP status used for codes that have no rule at
+// all (no override, reason rule or default). The default is 500.
+//
+// New fails unless http is a valid status (100-599).
+func WithHTTPFallback(http int) Option {
+	return func(b *builder) { b.fallbackHTTP = http }
+}
+
+// WithGRPCFallback sets the gRPC status used for codes that have no rule at
+// all. The default is codes.Internal.
+//
+// New fails unless grpc is a known gRPC code.
+func WithGRPCFallback(grpc int) Option {
+	return func(b *builder) { b.fallbackGRPC = codes.Code(grpc) }
+}
+
+// OnFallback registers fn to be called whenever a lookup ends in the global
+// fallback, e.g. to alert when an unmapped code reaches the edge. It runs
+// synchronously on the lookup path, at most once per Status call, and must
+// be safe for concurrent use. Explain does not trigger it.
+func OnFallback(fn func(c code.Code, r reason.Reason)) Option {
+	return func(b *builder) { b.onFallback = fn }
+}
+
+// WithPrecedence sets how per-code overrides rank against per-reason rules
+// (exact and prefix). The default is PrefixFirst; New fails for any other
+// value than PrefixFirst and OverrideFirst.
+func WithPrecedence(p Precedence) Option {
+	return func(b *builder) { b.precedence = p }
+}
+
+// WithStrict makes New run Lint on the configured rules and fail with an
+// error wrapping ErrLint if any finding has SeverityError, e.g. a rule that
+// can never fire or a prefix registered twice with different statuses.
+func WithStrict() Option {
+	return func(b *builder) { b.strict = true }
+}
+
+// WithGrammar sets the reason grammar used to validate prefix rules and to
+// match reasons, e.g. to allow five-level reasons or digit-first segments.
+// The default is reason.DefaultGrammar.
+func WithGrammar(g reason.Grammar) Option {
+	return func(b *builder) { b.grammar = g }
+}
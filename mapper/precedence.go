@@ -0,0 +1,61 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+// Precedence ranks per-code overrides against per-reason rules (exact and
+// prefix). Defaults always rank below both; see WithPrecedence.
+type Precedence uint8
+
+const (
+	// PrefixFirst lets exact and prefix rules win over the code's override:
+	// the override only applies to reasons no reason rule matches. It is the
+	// default, so a specific reason can refine a code-wide override.
+	PrefixFirst Precedence = iota
+
+	// OverrideFirst lets the code's override win over every reason rule, so
+	// an override pins the status of the code regardless of the reason.
+	OverrideFirst
+)
+
+// String returns "prefix-first" or "override-first".
+func (p Precedence) String() string {
+	switch p {
+	case PrefixFirst:
+		return "prefix-first"
+	case OverrideFirst:
+		return "override-first"
+	default:
+		return "unknown"
+	}
+}
+
+// valid reports whether p is PrefixFirst or OverrideFirst.
+func (p Precedence) valid() bool {
+	return p == PrefixFirst || p == OverrideFirst
+}
+
+// parsePrecedence is the inverse of Precedence.String.
+func parsePrecedence(s string) (Precedence, bool) {
+	switch s {
+	case "prefix-first":
+		return PrefixFirst, true
+	case "override-first":
+		return OverrideFirst, true
+	default:
+		return 0, false
+	}
+}
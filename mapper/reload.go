@@ -0,0 +1,221 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+	"google.golang.org/grpc/codes"
+)
+
+// Loader builds a fresh mapper snapshot, e.g. from a configuration file.
+type Loader func() (apis.Mapper, error)
+
+// FileLoader returns a Loader that reads the JSON document at path with
+// LoadFile, applying opts before the document's rules.
+func FileLoader(path string, opts ...Option) Loader {
+	return func() (apis.Mapper, error) { return LoadFile(path, opts...) }
+}
+
+// ReloadOption configures a Reloadable.
+type ReloadOption func(*Reloadable)
+
+// WithReloadErrorHandler sets the callback invoked when a reload fails.
+// The Reloadable keeps serving the last good snapshot in that case.
+func WithReloadErrorHandler(fn func(error)) ReloadOption {
+	return func(r *Reloadable) { r.onError = fn }
+}
+
+// Reloadable is an apis.Mapper whose rules can be replaced at run time.
+//
+// It holds the current immutable snapshot in an atomic.Pointer: lookups are
+// a single atomic load followed by the snapshot's own lock-free resolution,
+// and Reload swaps in a new snapshot only after it was built successfully.
+// A failed reload leaves the last good snapshot in place and is reported to
+// the handler set with WithReloadErrorHandler.
+//
+// Reloadable also implements apis.ReverseMapper and apis.Explainer by
+// delegating to the current snapshot.
+type Reloadable struct {
+	cur     atomic.Pointer[snapshot]
+	load    Loader
+	onError func(error)
+
+	// mu serializes Reload calls; readers never take it.
+	mu sync.Mutex
+}
+
+// snapshot boxes an apis.Mapper for atomic.Pointer.
+type snapshot struct {
+	m apis.Mapper
+}
+
+var (
+	_ apis.Mapper        = (*Reloadable)(nil)
+	_ apis.ReverseMapper = (*Reloadable)(nil)
+	_ apis.Explainer     = (*Reloadable)(nil)
+)
+
+// NewReloadable builds the initial snapshot with load and returns a
+// Reloadable serving it. An error from the initial load, or a nil mapper, is
+// returned as an error, since there is no previous snapshot to fall back to.
+func NewReloadable(load Loader, opts ...ReloadOption) (*Reloadable, error) {
+	if load == nil {
+		return nil, errors.New("mapper: nil Loader")
+	}
+	r := &Reloadable{load: load}
+	for _, opt := range opts {
+		opt(r)
+	}
+	m, err := r.loadMapper()
+	if err != nil {
+		return nil, err
+	}
+	r.cur.Store(&snapshot{m: m})
+	return r, nil
+}
+
+// Reload builds a new snapshot and swaps it in. On failure the current
+// snapshot is kept, the error handler is called and the error returned.
+func (r *Reloadable) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, err := r.loadMapper()
+	if err != nil {
+		if r.onError != nil {
+			r.onError(err)
+		}
+		return err
+	}
+	r.cur.Store(&snapshot{m: m})
+	return nil
+}
+
+// loadMapper calls the Loader and rejects a nil mapper, which would make
+// every later lookup panic.
+func (r *Reloadable) loadMapper() (apis.Mapper, error) {
+	m, err := r.load()
+	if err == nil && m == nil {
+		err = errors.New("mapper: Loader returned a nil mapper")
+	}
+	return m, err
+}
+
+// Watch reloads once, then polls the file at path every interval and calls
+// Reload when its modification time or size changes. It blocks until ctx is done and then
+// returns ctx.Err(). Failed reloads and stat errors are reported to the
+// error handler; watching continues either way.
+//
+// Watch is meant to run in its own goroutine, next to a Reloadable built
+// with FileLoader for the same path. A non-positive interval means one second.
+func (r *Reloadable) Watch(ctx context.Context, path string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	// Reload once up front so that changes made between building the
+	// Reloadable and starting Watch are not missed.
+	last, _ := os.Stat(path)
+	_ = r.Reload()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+		fi, err := os.Stat(path)
+		if err != nil {
+			if r.onError != nil {
+				r.onError(err)
+			}
+			continue
+		}
+		if last != nil && fi.ModTime().Equal(last.ModTime()) && fi.Size() == last.Size() {
+			continue
+		}
+		last = fi
+		_ = r.Reload()
+	}
+}
+
+// Current returns the snapshot serving lookups right now.
+func (r *Reloadable) Current() apis.Mapper { return r.cur.Load().m }
+
+// HTTPStatus implements apis.Mapper using the current snapshot.
+func (r *Reloadable) HTTPStatus(c code.Code, rs reason.Reason) int {
+	return r.Current().HTTPStatus(c, rs)
+}
+
+// GRPCStatus implements apis.Mapper using the current snapshot.
+func (r *Reloadable) GRPCStatus(c code.Code, rs reason.Reason) codes.Code {
+	return r.Current().GRPCStatus(c, rs)
+}
+
+// Status implements apis.Mapper. Both statuses come from the same snapshot,
+// even if a reload happens concurrently.
+func (r *Reloadable) Status(c code.Code, rs reason.Reason) apis.Status {
+	return r.Current().Status(c, rs)
+}
+
+// Explain implements apis.Mapper using the current snapshot.
+func (r *Reloadable) Explain(c code.Code, rs reason.Reason) string {
+	return r.Current().Explain(c, rs)
+}
+
+// Resolve implements apis.Explainer using the current snapshot. Snapshots
+// that are not explainers only report the resolved values, with no tier.
+func (r *Reloadable) Resolve(c code.Code, rs reason.Reason) apis.Resolution {
+	cur := r.Current()
+	if x, ok := cur.(apis.Explainer); ok {
+		return x.Resolve(c, rs)
+	}
+	st := cur.Status(c, rs)
+	return apis.Resolution{
+		Code:   c,
+		Reason: rs,
+		HTTP:   apis.Decision[int]{Value: st.HTTP},
+		GRPC:   apis.Decision[codes.Code]{Value: st.GRPC},
+	}
+}
+
+// FromHTTP implements apis.ReverseMapper. Snapshots that are not reverse
+// mappers resolve like an empty mapper (see mapper.FromHTTP).
+func (r *Reloadable) FromHTTP(status int) code.Code {
+	if rm, ok := r.Current().(apis.ReverseMapper); ok {
+		return rm.FromHTTP(status)
+	}
+	return (&mapper{}).FromHTTP(status)
+}
+
+// FromGRPC implements apis.ReverseMapper; see FromHTTP.
+func (r *Reloadable) FromGRPC(c codes.Code) code.Code {
+	if rm, ok := r.Current().(apis.ReverseMapper); ok {
+		return rm.FromGRPC(c)
+	}
+	return (&mapper{}).FromGRPC(c)
+}
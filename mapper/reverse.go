@@ -0,0 +1,127 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package mapper
+
+import (
+	"cmp"
+	"slices"
+
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"google.golang.org/grpc/codes"
+)
+
+var _ apis.ReverseMapper = (*mapper)(nil)
+
+// defaultReversePreference breaks ties between codes that share a transport
+// status under the library defaults, e.g. 400 (invalid, missing, ...),
+// 409 (already_exists, conflict, stale_version) or gRPC FailedPrecondition.
+// The most generic code of each group comes first.
+var defaultReversePreference = []code.Code{
+	code.Internal,
+	code.Invalid,
+	code.NotFound,
+	code.Conflict,
+	code.PreconditionFailed,
+	code.Unauthenticated,
+	code.PermissionDenied,
+	code.RateLimited,
+	code.Unavailable,
+	code.Timeout,
+	code.Canceled,
+}
+
+// FromHTTP implements apis.ReverseMapper.
+//
+// The inverse index is built from the code-level rules (override, else
+// default) when the mapper is created; reason prefixes are ignored since a
+// status alone cannot recover a reason. When several codes produce status,
+// the first one found in WithHTTPReversePreference, then in the library
+// preference order wins; remaining ties go to the lexically smallest code.
+//
+// Statuses no code maps to resolve by class: code.Empty below 400,
+// code.Invalid for 4xx, code.Internal otherwise.
+func (m *mapper) FromHTTP(status int) code.Code {
+	if c, ok := m.httpReverse[status]; ok {
+		return c
+	}
+	switch {
+	case status < 400:
+		return code.Empty
+	case status < 500:
+		return code.Invalid
+	default:
+		return code.Internal
+	}
+}
+
+// FromGRPC implements apis.ReverseMapper with the same rules as FromHTTP,
+// using WithGRPCReversePreference. codes.OK resolves to code.Empty and any
+// other unmapped code to code.Internal.
+func (m *mapper) FromGRPC(c codes.Code) code.Code {
+	if cd, ok := m.grpcReverse[c]; ok {
+		return cd
+	}
+	if c == codes.OK {
+		return code.Empty
+	}
+	return code.Internal
+}
+
+// buildReverse inverts the code-level rules (override, else default) into a
+// status -> code index, resolving ambiguities with pref followed by
+// defaultReversePreference.
+func buildReverse[S comparable](defaults, overrides map[code.Code]S, pref []code.Code) map[S]code.Code {
+	candidates := make(map[S][]code.Code)
+	for c, v := range defaults {
+		if _, ok := overrides[c]; !ok {
+			candidates[v] = append(candidates[v], c)
+		}
+	}
+	for c, v := range overrides {
+		candidates[v] = append(candidates[v], c)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	rank := make(map[code.Code]int, len(pref)+len(defaultReversePreference))
+	for i, c := range slices.Concat(pref, defaultReversePreference) {
+		if _, ok := rank[c]; !ok {
+			rank[c] = i
+		}
+	}
+	byPreference := func(a, b code.Code) int {
+		ra, okA := rank[a]
+		rb, okB := rank[b]
+		switch {
+		case okA && okB:
+			return cmp.Compare(ra, rb)
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return cmp.Compare(a, b)
+	}
+
+	out := make(map[S]code.Code, len(candidates))
+	for s, cs := range candidates {
+		out[s] = slices.MinFunc(cs, byPreference)
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"fmt"
+	"strings"
+
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// Policy picks the overall Code of an aggregate error from its children.
+// It is always called with at least two children.
+type Policy func(errs []*Error) code.Code
+
+var (
+	// PolicyFirst uses the code of the first child.
+	PolicyFirst Policy = func(errs []*Error) code.Code { return errs[0].Code }
+
+	// PolicyMostSevere uses the most severe code (see severityRank); ties
+	// go to the earlier child. Server-side failures outrank client
+	// mistakes, so a batch with one "unavailable" and nine "invalid" reports
+	// "unavailable", and among client mistakes an auth failure outranks a
+	// quota, which outranks a resource conflict or plain invalid input.
+	PolicyMostSevere Policy = func(errs []*Error) code.Code {
+		best, bestRank := errs[0].Code, severityRank(errs[0].Code)
+		for _, e := range errs[1:] {
+			if r := severityRank(e.Code); r > bestRank {
+				best, bestRank = e.Code, r
+			}
+		}
+		return best
+	}
+
+	// PolicyMajority uses the most frequent code; ties go to the code that
+	// appears first.
+	PolicyMajority Policy = func(errs []*Error) code.Code {
+		counts := make(map[code.Code]int, len(errs))
+		best, bestN := errs[0].Code, 0
+		for _, e := range errs {
+			counts[e.Code]++
+		}
+		for _, e := range errs {
+			if n := counts[e.Code]; n > bestN {
+				best, bestN = e.Code, n
+			}
+		}
+		return best
+	}
+)
+
+// Multi aggregates several errors, e.g. from batch validation or fan-out
+// calls. It is normally not used directly: Join wraps it into a *Error whose
+// Err is the *Multi, so the aggregate flows through the usual adapters.
+//
+// errors.Is and errors.As inspect every classified child and, through its
+// Err, the original error it was classified from.
+type Multi struct {
+	// items are the non-nil inputs in order, classified with Classify.
+	items []*Error
+}
+
+// Error joins the children's messages with "; ".
+func (m *Multi) Error() string {
+	if m == nil || len(m.items) == 0 {
+		return "<nil>"
+	}
+	parts := make([]string, len(m.items))
+	for i, e := range m.items {
+		parts[i] = e.Error()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Unwrap returns the classified children, enabling errors.Is / errors.As
+// over all of them. Matching by code (HasCode, errors.Is with a *Error
+// target) therefore sees the same codes as the Policy did, including the
+// code.Internal given to plain errors; the plain errors themselves remain
+// reachable through each child's Err.
+func (m *Multi) Unwrap() []error {
+	if m == nil {
+		return nil
+	}
+	out := make([]error, len(m.items))
+	for i, e := range m.items {
+		out[i] = e
+	}
+	return out
+}
+
+// Errors returns the children as *Error values (non-derrors inputs are
+// classified with Classify). The returned slice is a copy.
+func (m *Multi) Errors() []*Error {
+	if m == nil {
+		return nil
+	}
+	out := make([]*Error, len(m.items))
+	copy(out, m.items)
+	return out
+}
+
+// Len returns the number of children.
+func (m *Multi) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.items)
+}
+
+// Join aggregates errs into a single *Error using PolicyMostSevere.
+// See JoinPolicy for details.
+func Join(errs ...error) *Error {
+	return JoinPolicy(PolicyMostSevere, errs...)
+}
+
+// JoinPolicy aggregates errs into a single *Error whose Code is chosen by p.
+//
+// Nil inputs are skipped. With no remaining errors JoinPolicy returns nil;
+// with exactly one it returns Classify of that error, so single failures do
+// not get wrapped needlessly.
+//
+// Otherwise the result carries:
+//
+//   - Code:    p(children);
+//   - Reason:  the children's common reason, if they all share one;
+//   - Message: "<n> errors occurred";
+//   - Err:     a *Multi holding every child.
+func JoinPolicy(p Policy, errs ...error) *Error {
+	if p == nil {
+		p = PolicyMostSevere
+	}
+	m := &Multi{}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		m.items = append(m.items, Classify(err))
+	}
+	switch len(m.items) {
+	case 0:
+		return nil
+	case 1:
+		return m.items[0]
+	}
+
+	r := m.items[0].Reason
+	for _, e := range m.items[1:] {
+		if e.Reason != r {
+			r = reason.Empty
+			break
+		}
+	}
+	return &Error{
+		Code:    p(m.items),
+		Reason:  r,
+		Message: fmt.Sprintf("%d errors occurred", len(m.items)),
+		Err:     m,
+	}
+}
+
+// warningRanks orders the categories of code.SeverityWarning codes for
+// severityRank.
+var warningRanks = map[code.Category]int{
+	code.CategoryClient:   0,
+	code.CategoryResource: 1,
+	code.CategoryQuota:    2,
+	code.CategoryAuth:     3,
+}
+
+// severityRank orders codes for PolicyMostSevere; higher is more severe.
+// Codes are ranked by code.Info severity, and caller mistakes
+// (code.SeverityWarning) additionally by category through warningRanks.
+// Codes without metadata rank like code.Internal.
+func severityRank(c code.Code) int {
+	m := code.Info(c)
+	rank := int(m.Severity) * (len(warningRanks) + 1)
+	if m.Severity == code.SeverityWarning {
+		rank += warningRanks[m.Category]
+	}
+	return rank
+}
@@ -16,7 +16,7 @@
 
 package derrors
 
-import "dirpx.dev/derrors/reason"
+import "dirpx.dev/derrors/v2/reason"
 
 // Option is a functional option for constructing or transforming an Error.
 // It always takes an *Error and returns a (possibly new) *Error.
@@ -53,3 +53,12 @@ func WithCauseOption(err error) Option {
 		return e.WithCause(err)
 	}
 }
+
+// WithMessageOption replaces the human message on construction.
+// Intended to be used with E(...) and with constructors that fill in a
+// default message, such as catalog.New.
+func WithMessageOption(msg string) Option {
+	return func(e *Error) *Error {
+		return e.WithMessage(msg)
+	}
+}
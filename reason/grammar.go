@@ -0,0 +1,176 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package reason
+
+import "strings"
+
+// Grammar describes which reasons are valid. The package-level functions
+// (Parse, Validate, ValidSegment, Join, ParsePattern) use DefaultGrammar;
+// teams with deeper hierarchies or version-like segments build their own:
+//
+//	g := reason.Grammar{MaxDepth: 5, DigitFirst: true}
+//	r, err := g.Parse("billing.v2.invoice.pdf.render")
+//
+// Zero-valued limits fall back to the defaults, so a Grammar only needs to
+// spell out what it changes. Grammar values are immutable and safe for
+// concurrent use.
+type Grammar struct {
+	// MaxDepth is the maximum number of segments. Default: MaxDepth (4).
+	MaxDepth int
+
+	// MinLength and MaxLength bound the byte length of a non-empty reason.
+	// Defaults: MinLength (3) and MaxLength (128).
+	MinLength int
+	MaxLength int
+
+	// DigitFirst allows segments to start with a digit ("v2", "2fa").
+	// By default segments must start with a lowercase ASCII letter.
+	DigitFirst bool
+}
+
+// DefaultGrammar is the grammar behind the package-level functions:
+// 1..4 segments of [a-z][a-z0-9_]*, 3..128 bytes in total.
+var DefaultGrammar = Grammar{MaxDepth: MaxDepth, MinLength: MinLength, MaxLength: MaxLength}
+
+// Parse normalizes s like the package-level Parse and validates it against g.
+// The empty string yields Empty without error.
+func (g Grammar) Parse(s string) (Reason, error) {
+	s = Normalize(s)
+	if s == "" {
+		return Empty, nil
+	}
+	if err := g.validate(s); err != nil {
+		return Empty, err
+	}
+	return Reason(s), nil
+}
+
+// Validate checks whether r is canonical under g. Empty is valid.
+func (g Grammar) Validate(r Reason) error {
+	if r == Empty {
+		return nil
+	}
+	return g.validate(string(r))
+}
+
+// ValidSegment reports whether seg is a valid segment under g: lowercase
+// ASCII letters, digits and underscores, starting with a letter (or, with
+// DigitFirst, a digit).
+func (g Grammar) ValidSegment(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	if c := seg[0]; !(c >= 'a' && c <= 'z') && !(g.DigitFirst && c >= '0' && c <= '9') {
+		return false
+	}
+	for i := 1; i < len(seg); i++ {
+		c := seg[i]
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '_' {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// Join builds a reason from segments under g; see the package-level Join.
+func (g Grammar) Join(segs ...string) (Reason, error) {
+	if len(segs) == 0 {
+		return Empty, nil
+	}
+	if len(segs) > g.maxDepth() {
+		return Empty, ErrReasonTooDeep
+	}
+	norm := make([]string, len(segs))
+	for i, seg := range segs {
+		seg = Normalize(seg)
+		if !g.ValidSegment(seg) {
+			return Empty, ErrReasonInvalidFormat
+		}
+		norm[i] = seg
+	}
+	s := strings.Join(norm, ".")
+	if err := g.validate(s); err != nil {
+		return Empty, err
+	}
+	return Reason(s), nil
+}
+
+// Append returns r extended by one segment under g; see Reason.Append.
+func (g Grammar) Append(r Reason, seg string) (Reason, error) {
+	return g.Join(append(r.Segments(), seg)...)
+}
+
+// ParsePattern parses a reason pattern whose literal segments and depth
+// follow g; see the package-level ParsePattern.
+func (g Grammar) ParsePattern(s string) (Pattern, error) {
+	s = Normalize(s)
+	if s == "" {
+		return Pattern{}, ErrPatternInvalid
+	}
+	p := Pattern{raw: s}
+	segs := strings.Split(s, ".")
+	for i, seg := range segs {
+		switch {
+		case seg == "**" && i == len(segs)-1:
+			p.open = true
+		case seg == "*" || g.ValidSegment(seg):
+			p.segs = append(p.segs, seg)
+		default:
+			return Pattern{}, ErrPatternInvalid
+		}
+	}
+	if len(p.segs) > g.maxDepth() {
+		return Pattern{}, ErrPatternInvalid
+	}
+	return p, nil
+}
+
+// Resolved returns g with every zero-valued limit replaced by its default.
+func (g Grammar) Resolved() Grammar {
+	if g.MaxDepth <= 0 {
+		g.MaxDepth = MaxDepth
+	}
+	if g.MinLength <= 0 {
+		g.MinLength = MinLength
+	}
+	if g.MaxLength <= 0 {
+		g.MaxLength = MaxLength
+	}
+	return g
+}
+
+// validate checks length, segments and depth of a non-empty reason.
+func (g Grammar) validate(s string) error {
+	g = g.Resolved()
+	if len(s) < g.MinLength || len(s) > g.MaxLength {
+		return ErrReasonInvalidLength
+	}
+	depth := 0
+	for seg := range strings.SplitSeq(s, ".") {
+		if !g.ValidSegment(seg) {
+			return ErrReasonInvalidFormat
+		}
+		depth++
+	}
+	if depth > g.MaxDepth {
+		return ErrReasonTooDeep
+	}
+	return nil
+}
+
+func (g Grammar) maxDepth() int { return g.Resolved().MaxDepth }
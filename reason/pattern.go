@@ -0,0 +1,156 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package reason
+
+import (
+	"cmp"
+	"errors"
+	"strings"
+)
+
+// ErrPatternInvalid is returned by ParsePattern for malformed patterns.
+var ErrPatternInvalid = errors.New("derrors: invalid reason pattern")
+
+// Pattern is a parsed reason glob, using the same segment grammar as the
+// mapper's prefix rules:
+//
+//   - a literal segment matches itself (see ValidSegment);
+//   - "*" matches exactly one segment;
+//   - "**", allowed only as the last segment, matches zero or more
+//     remaining segments.
+//
+// Examples:
+//
+//	"auth.*.expired"  matches "auth.jwt.expired", not "auth.jwt.x.expired"
+//	"storage.**"      matches "storage", "storage.pg", "storage.pg.connect"
+//
+// The zero Pattern matches nothing; build patterns with ParsePattern.
+type Pattern struct {
+	raw  string
+	segs []string // fixed segments: literals and "*"
+	open bool     // trailing "**"
+}
+
+// Specificity ranks patterns; see ComparePatterns.
+type Specificity struct {
+	// Depth is the number of fixed segments (literals and "*").
+	Depth int
+	// Literals is the number of literal segments.
+	Literals int
+	// Open reports a trailing "**".
+	Open bool
+}
+
+// ParsePattern normalizes s like Normalize and parses it as a Pattern under
+// DefaultGrammar. It fails with ErrPatternInvalid for an empty pattern,
+// invalid segments, "**" anywhere but at the end, or more than MaxDepth
+// fixed segments. Use Grammar.ParsePattern for other grammars.
+func ParsePattern(s string) (Pattern, error) {
+	return DefaultGrammar.ParsePattern(s)
+}
+
+// MustParsePattern is the panic-on-error variant of ParsePattern, for
+// package-level declarations.
+func MustParsePattern(s string) Pattern {
+	p, err := ParsePattern(s)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String returns the canonical form of the pattern.
+func (p Pattern) String() string { return p.raw }
+
+// Match reports whether p matches the whole reason r.
+func (p Pattern) Match(r Reason) bool {
+	n, ok := p.matchFixed(r)
+	if !ok {
+		return false
+	}
+	return n == len(r) || p.open
+}
+
+// MatchPrefix reports whether p matches r or a segment prefix of r, which is
+// how the mapper applies its prefix rules: "auth.*" matches
+// "auth.jwt.expired".
+func (p Pattern) MatchPrefix(r Reason) bool {
+	_, ok := p.matchFixed(r)
+	return ok
+}
+
+// Specificity returns the ranking of p.
+func (p Pattern) Specificity() Specificity {
+	s := Specificity{Depth: len(p.segs), Open: p.open}
+	for _, seg := range p.segs {
+		if seg != "*" {
+			s.Literals++
+		}
+	}
+	return s
+}
+
+// ComparePatterns orders patterns by specificity and returns a positive
+// number when a is more specific than b, a negative one when it is less
+// specific, and 0 for a tie.
+//
+// Deeper patterns win first, mirroring the mapper's longest-prefix rule;
+// then patterns with more literal segments; then patterns without "**".
+// To sort the most specific pattern first:
+//
+//	slices.SortFunc(ps, func(a, b reason.Pattern) int { return reason.ComparePatterns(b, a) })
+func ComparePatterns(a, b Pattern) int {
+	sa, sb := a.Specificity(), b.Specificity()
+	if c := cmp.Compare(sa.Depth, sb.Depth); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(sa.Literals, sb.Literals); c != 0 {
+		return c
+	}
+	switch {
+	case sa.Open == sb.Open:
+		return 0
+	case sa.Open:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// matchFixed matches the fixed segments of p against the leading segments
+// of r. It returns the byte length of r they consumed.
+func (p Pattern) matchFixed(r Reason) (int, bool) {
+	if p.raw == "" {
+		return 0, false
+	}
+	s := string(r)
+	off := 0
+	for _, want := range p.segs {
+		if off >= len(s) {
+			return 0, false // r has fewer segments than p
+		}
+		seg, _, _ := strings.Cut(s[off:], ".")
+		if want != "*" && want != seg {
+			return 0, false
+		}
+		off += len(seg) + 1
+	}
+	if off > 0 {
+		off-- // drop the separator after the last consumed segment
+	}
+	return off, true
+}
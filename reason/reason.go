@@ -20,7 +20,6 @@ import (
 	"bytes"
 	"encoding"
 	"errors"
-	"regexp"
 	"strings"
 )
 
@@ -43,7 +42,7 @@ import (
 type Reason string
 
 // MinLength and MaxLength define the allowed length range for a canonical
-// reason string.
+// reason string under DefaultGrammar; see Grammar to change them.
 //
 // We allow reasons to be a bit longer than codes, because they often contain
 // multiple segments (module.component.operation).
@@ -59,44 +58,15 @@ const (
 	MaxLength = 128
 )
 
-const (
-	// reasonFmt is the canonical regular expression used to validate reasons.
-	//
-	// We accept 1 to 4 segments, dot-separated, each segment:
-	//
-	//   - starts with a lowercase ASCII letter [a-z]
-	//   - continues with lowercase letters, digits, or underscore [a-z0-9_]*
-	//
-	// Examples that match:
-	//
-	//	"apimachinery.schema.gvk.parse"
-	//	"storage.pg.connect"
-	//	"auth.jwt.verify"
-	//	"network.dns"
-	//
-	// Examples that DO NOT match:
-	//
-	//	"ApiMachinery..."  (uppercase)
-	//	"apimachinery/schema" (slash)
-	//	"apimachinery..schema" (empty segment)
-	//	"1schema.parse" (digit first)
-	//
-	// NOTE: empty string ("") is treated separately as "optional reason" and does
-	// not go through this regexp.
-	reasonFmt = `^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*){0,3}$`
-)
-
-var (
-	// reasonRe is the compiled regexp for the above pattern.
-	reasonRe = regexp.MustCompile(reasonFmt)
-)
-
 var (
 	// ErrReasonInvalidFormat is returned when a reason does not conform to
 	// the expected format.
 	ErrReasonInvalidFormat = errors.New("derrors: invalid reason format")
 	// ErrReasonInvalidLength is returned when a reason is too short or too long.
 	ErrReasonInvalidLength = errors.New("derrors: invalid reason length")
+	// ErrReasonTooDeep is returned when a reason has more segments than the
+	// grammar allows.
+	ErrReasonTooDeep = errors.New("derrors: reason has too many segments")
 )
 
 // Ensure Reason implements encoding.TextMarshaler / encoding.TextUnmarshaler.
@@ -138,14 +108,7 @@ func Normalize(s string) string {
 // Parse also accepts the empty string and returns reason.Empty without error.
 // This is what makes Reason an "optional" part of the error model.
 func Parse(s string) (Reason, error) {
-	s = Normalize(s)
-	if s == "" {
-		return Empty, nil
-	}
-	if err := validate(s); err != nil {
-		return Empty, err
-	}
-	return Reason(s), nil
+	return DefaultGrammar.Parse(s)
 }
 
 // MustParse is the panic-on-error variant of Parse. It is useful for
@@ -170,10 +133,7 @@ func MustParse(s string) Reason {
 // this type is to be optional. If you need to enforce "must be non-empty",
 // add that check at call site.
 func Validate(r Reason) error {
-	if r == Empty {
-		return nil
-	}
-	return validate(string(r))
+	return DefaultGrammar.Validate(r)
 }
 
 // String returns the canonical string representation of the reason.
@@ -208,14 +168,3 @@ func (r *Reason) UnmarshalText(text []byte) error {
 	*r = parsed
 	return nil
 }
-
-// validate is the internal helper that checks length and format.
-func validate(s string) error {
-	if len(s) < MinLength || len(s) > MaxLength {
-		return ErrReasonInvalidLength
-	}
-	if !reasonRe.MatchString(s) {
-		return ErrReasonInvalidFormat
-	}
-	return nil
-}
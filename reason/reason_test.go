@@ -18,6 +18,8 @@ package reason
 
 import (
 	"encoding"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -221,3 +223,162 @@ func TestReason_ImplementsTextInterfaces(t *testing.T) {
 	var _ encoding.TextMarshaler = (*Reason)(nil)
 	var _ encoding.TextUnmarshaler = (*Reason)(nil)
 }
+
+func TestReason_SegmentsDepthParent(t *testing.T) {
+	tests := []struct {
+		in     Reason
+		segs   []string
+		depth  int
+		parent Reason
+	}{
+		{"", nil, 0, ""},
+		{"storage", []string{"storage"}, 1, ""},
+		{"storage.pg.connect", []string{"storage", "pg", "connect"}, 3, "storage.pg"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.in), func(t *testing.T) {
+			if got := tt.in.Segments(); !slices.Equal(got, tt.segs) {
+				t.Fatalf("Segments() = %v, want %v", got, tt.segs)
+			}
+			if got := slices.Collect(tt.in.SegmentSeq()); !slices.Equal(got, tt.segs) {
+				t.Fatalf("SegmentSeq() = %v, want %v", got, tt.segs)
+			}
+			if got := tt.in.Depth(); got != tt.depth {
+				t.Fatalf("Depth() = %d, want %d", got, tt.depth)
+			}
+			if got := tt.in.Parent(); got != tt.parent {
+				t.Fatalf("Parent() = %q, want %q", got, tt.parent)
+			}
+		})
+	}
+}
+
+func TestReason_HasPrefix(t *testing.T) {
+	tests := []struct {
+		r, p Reason
+		want bool
+	}{
+		{"storage.pg.connect", "storage.pg", true},
+		{"storage.pg.connect", "storage.pg.connect", true},
+		{"storage.pgx", "storage.pg", false},
+		{"storage", "storage.pg", false},
+		{"storage.pg", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		if got := tt.r.HasPrefix(tt.p); got != tt.want {
+			t.Errorf("%q.HasPrefix(%q) = %v, want %v", tt.r, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestJoinAndAppend(t *testing.T) {
+	r, err := Join("Storage", "pg")
+	if err != nil || r != "storage.pg" {
+		t.Fatalf("Join = %q, %v", r, err)
+	}
+	r, err = r.Append("connect-timeout")
+	if err != nil || r != "storage.pg.connect_timeout" {
+		t.Fatalf("Append = %q, %v", r, err)
+	}
+	if r, err := Join(); err != nil || r != Empty {
+		t.Fatalf("Join() = %q, %v", r, err)
+	}
+
+	r4, _ := r.Append("retry")
+	if _, err := r4.Append("again"); err != ErrReasonTooDeep {
+		t.Fatalf("5 segments: err = %v, want ErrReasonTooDeep", err)
+	}
+	if _, err := Join("storage", "pg.connect"); err != ErrReasonInvalidFormat {
+		t.Fatalf("dotted segment: err = %v, want ErrReasonInvalidFormat", err)
+	}
+	if _, err := Join("1pg"); err != ErrReasonInvalidFormat {
+		t.Fatalf("digit first: err = %v, want ErrReasonInvalidFormat", err)
+	}
+	if _, err := Join(strings.Repeat("a", 100), strings.Repeat("b", 30)); err != ErrReasonInvalidLength {
+		t.Fatalf("too long: err = %v, want ErrReasonInvalidLength", err)
+	}
+}
+
+func TestPattern_Match(t *testing.T) {
+	tests := []struct {
+		pat    string
+		r      Reason
+		full   bool
+		prefix bool
+	}{
+		{"auth.*.expired", "auth.jwt.expired", true, true},
+		{"auth.*.expired", "auth.jwt.x.expired", false, false},
+		{"auth.*.expired", "auth.jwt", false, false},
+		{"auth.*", "auth.jwt.expired", false, true},
+		{"storage.**", "storage", true, true},
+		{"storage.**", "storage.pg.connect", true, true},
+		{"storage.**", "storagex", false, false},
+		{"**", "", true, true},
+		{"**", "any.thing", true, true},
+		{"Auth/JWT", "auth.jwt", true, true},
+	}
+	for _, tt := range tests {
+		p := MustParsePattern(tt.pat)
+		if got := p.Match(tt.r); got != tt.full {
+			t.Errorf("%q.Match(%q) = %v, want %v", tt.pat, tt.r, got, tt.full)
+		}
+		if got := p.MatchPrefix(tt.r); got != tt.prefix {
+			t.Errorf("%q.MatchPrefix(%q) = %v, want %v", tt.pat, tt.r, got, tt.prefix)
+		}
+	}
+	if (Pattern{}).Match("") {
+		t.Error("zero Pattern must match nothing")
+	}
+}
+
+func TestParsePattern_Invalid(t *testing.T) {
+	for _, s := range []string{"", "auth..jwt", "auth.**.jwt", "1auth", "a.b.c.d.e", "auth.j*"} {
+		if _, err := ParsePattern(s); err != ErrPatternInvalid {
+			t.Errorf("ParsePattern(%q) err = %v, want ErrPatternInvalid", s, err)
+		}
+	}
+}
+
+func TestComparePatterns(t *testing.T) {
+	ordered := []string{"auth.jwt.expired", "auth.*.expired", "auth.jwt", "auth.jwt.**", "auth.*", "auth.**", "**"}
+	ps := make([]Pattern, len(ordered))
+	for i, s := range ordered {
+		ps[len(ps)-1-i] = MustParsePattern(s)
+	}
+	slices.SortFunc(ps, func(a, b Pattern) int { return ComparePatterns(b, a) })
+	for i, p := range ps {
+		if p.String() != ordered[i] {
+			t.Fatalf("position %d: got %q, want %q", i, p, ordered[i])
+		}
+	}
+}
+
+func TestGrammar(t *testing.T) {
+	g := Grammar{MaxDepth: 5, DigitFirst: true}
+
+	if _, err := Parse("billing.v2.invoice.pdf.render"); err != ErrReasonTooDeep {
+		t.Fatalf("default Parse(5 segments) err = %v, want ErrReasonTooDeep", err)
+	}
+	if _, err := Parse("billing.2fa"); err != ErrReasonInvalidFormat {
+		t.Fatalf("default Parse(digit first) err = %v, want ErrReasonInvalidFormat", err)
+	}
+	if r, err := g.Parse("Billing.2FA.invoice.pdf.render"); err != nil || r != "billing.2fa.invoice.pdf.render" {
+		t.Fatalf("custom Parse = %q, %v", r, err)
+	}
+	if _, err := g.Parse("a.b.c.d.e.f"); err != ErrReasonTooDeep {
+		t.Fatalf("custom Parse(6 segments) err = %v, want ErrReasonTooDeep", err)
+	}
+	if err := (Grammar{MaxLength: 8}).Validate("storage.pg"); err != ErrReasonInvalidLength {
+		t.Fatalf("MaxLength=8 err = %v, want ErrReasonInvalidLength", err)
+	}
+	if r, err := g.Append("a.b.c.d", "v2"); err != nil || r != "a.b.c.d.v2" {
+		t.Fatalf("custom Append = %q, %v", r, err)
+	}
+	if p, err := g.ParsePattern("billing.*.2fa.**"); err != nil || !p.Match("billing.x.2fa.y.z") {
+		t.Fatalf("custom ParsePattern = %v, %v", p, err)
+	}
+	if got := (Grammar{}).Resolved(); got != DefaultGrammar {
+		t.Fatalf("zero Grammar resolves to %+v, want DefaultGrammar", got)
+	}
+}
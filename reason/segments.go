@@ -0,0 +1,103 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package reason
+
+import (
+	"iter"
+	"strings"
+)
+
+// MaxDepth is the default maximum number of segments in a reason; see
+// Grammar for deeper hierarchies.
+const MaxDepth = 4
+
+// ValidSegment reports whether seg is a valid reason segment under
+// DefaultGrammar: a lowercase ASCII letter followed by lowercase letters,
+// digits or underscores.
+func ValidSegment(seg string) bool { return DefaultGrammar.ValidSegment(seg) }
+
+// Segments returns the dot-separated segments of r, or nil for Empty.
+// Use SegmentSeq to iterate without allocating.
+func (r Reason) Segments() []string {
+	if r == Empty {
+		return nil
+	}
+	return strings.Split(string(r), ".")
+}
+
+// SegmentSeq returns an iterator over the segments of r. It does not
+// allocate and yields nothing for Empty.
+func (r Reason) SegmentSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s := string(r)
+		for s != "" {
+			seg, rest, found := strings.Cut(s, ".")
+			if !yield(seg) || !found {
+				return
+			}
+			s = rest
+		}
+	}
+}
+
+// Depth returns the number of segments of r; 0 for Empty.
+func (r Reason) Depth() int {
+	if r == Empty {
+		return 0
+	}
+	return strings.Count(string(r), ".") + 1
+}
+
+// Parent returns r without its last segment, or Empty when r has at most
+// one segment:
+//
+//	Reason("storage.pg.connect").Parent() // "storage.pg"
+func (r Reason) Parent() Reason {
+	i := strings.LastIndexByte(string(r), '.')
+	if i < 0 {
+		return Empty
+	}
+	return r[:i]
+}
+
+// HasPrefix reports whether p is a prefix of r on a segment boundary:
+// "storage.pg" is a prefix of "storage.pg.connect" but not of
+// "storage.pgx". Every reason, including Empty, has the Empty prefix.
+func (r Reason) HasPrefix(p Reason) bool {
+	if p == Empty {
+		return true
+	}
+	if !strings.HasPrefix(string(r), string(p)) {
+		return false
+	}
+	return len(r) == len(p) || r[len(p)] == '.'
+}
+
+// Append returns r extended by one segment under DefaultGrammar. The segment
+// is normalized like Normalize does; use Grammar.Append for other grammars.
+func (r Reason) Append(seg string) (Reason, error) {
+	return DefaultGrammar.Append(r, seg)
+}
+
+// Join builds a reason from segments under DefaultGrammar, normalizing each
+// one like Normalize does. Join() returns Empty. It fails with
+// ErrReasonInvalidFormat for an invalid segment, ErrReasonTooDeep for more
+// than MaxDepth segments and ErrReasonInvalidLength when the result is too
+// short or too long.
+func Join(segs ...string) (Reason, error) {
+	return DefaultGrammar.Join(segs...)
+}
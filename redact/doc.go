@@ -0,0 +1,36 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package redact provides a policy-driven implementation of apis.Redactor.
+//
+// A Policy decides, per error code, whether the message is replaced by a
+// generic text, which detail keys are dropped or masked, and whether causes
+// may be exposed. Two ready-made profiles cover the common cases:
+//
+//   - Public: for untrusted callers. Messages of server-fault codes (see
+//     code.Info: internal, unavailable, dependency_failed, and the transient
+//     timeout, not_ready, draining and overloaded) are hidden behind a
+//     generic text, and their details are
+//     dropped unless declared with derrors.KeyPublic. Sensitive keys
+//     (derrors.KeySensitive) are dropped, causes are never exposed.
+//   - Internal: for trusted callers (service-to-service, admin tools).
+//     Everything except sensitive keys passes through.
+//
+// Profiles are plain Policies and can be extended with options:
+//
+//	p := redact.Public(redact.WithMaskedKeys("email"))
+//	w := httpx.Writer{Mapper: m, Redactor: p}
+package redact
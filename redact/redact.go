@@ -0,0 +1,191 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"maps"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+// DefaultGenericMessage replaces hidden messages unless WithGenericMessage is used.
+const DefaultGenericMessage = "internal error"
+
+// DefaultMask replaces the Info values of masked detail keys unless WithMask is used.
+const DefaultMask = "***"
+
+// Policy is an immutable apis.Redactor configured with options.
+// The zero value exposes messages and details but no causes; sensitive keys
+// are dropped by every Policy.
+type Policy struct {
+	hidden            map[code.Code]struct{}
+	hideServer        bool
+	hideServerDetails bool
+	generic           string
+	drop              map[string]struct{}
+	mask              map[string]struct{}
+	maskStr           string
+	causes            bool
+}
+
+var _ apis.Redactor = (*Policy)(nil)
+
+// Option configures a Policy.
+type Option func(*Policy)
+
+// WithHiddenMessages replaces the messages of errors with any of the given
+// codes by the generic message.
+func WithHiddenMessages(cs ...code.Code) Option {
+	return func(p *Policy) {
+		for _, c := range cs {
+			p.hidden[c] = struct{}{}
+		}
+	}
+}
+
+// WithHiddenServerMessages replaces the messages of every code whose
+// code.Info reports a server fault by the generic message. Such messages
+// describe failures on the server side, and their text tends to leak
+// internals.
+func WithHiddenServerMessages() Option {
+	return func(p *Policy) { p.hideServer = true }
+}
+
+// WithHiddenServerDetails drops the details of errors whose code.Info reports
+// a server fault, except those stored under keys declared with
+// derrors.KeyPublic. Details of server-side failures (hosts, queries, limits
+// of internal pools) are rarely meant for the caller, unlike those of client
+// errors, which usually tell the caller what to fix.
+func WithHiddenServerDetails() Option {
+	return func(p *Policy) { p.hideServerDetails = true }
+}
+
+// WithGenericMessage sets the text used for hidden messages.
+func WithGenericMessage(msg string) Option {
+	return func(p *Policy) { p.generic = msg }
+}
+
+// WithDroppedKeys drops details whose Field equals one of keys.
+func WithDroppedKeys(keys ...string) Option {
+	return func(p *Policy) {
+		for _, k := range keys {
+			p.drop[k] = struct{}{}
+		}
+	}
+}
+
+// WithMaskedKeys keeps details whose Field equals one of keys but replaces
+// all their Info values with the mask.
+func WithMaskedKeys(keys ...string) Option {
+	return func(p *Policy) {
+		for _, k := range keys {
+			p.mask[k] = struct{}{}
+		}
+	}
+}
+
+// WithMask sets the text used for masked Info values.
+func WithMask(s string) Option {
+	return func(p *Policy) { p.maskStr = s }
+}
+
+// WithCauses controls whether causes may be exposed.
+func WithCauses(expose bool) Option {
+	return func(p *Policy) { p.causes = expose }
+}
+
+// New builds a Policy from options. Without options it behaves like the
+// zero value.
+func New(opts ...Option) *Policy {
+	p := &Policy{
+		hidden:  map[code.Code]struct{}{},
+		generic: DefaultGenericMessage,
+		drop:    map[string]struct{}{},
+		mask:    map[string]struct{}{},
+		maskStr: DefaultMask,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// Public returns the profile for untrusted callers: messages of server-fault
+// codes (see code.Info) are hidden, so are their details unless declared
+// with derrors.KeyPublic, and causes are not exposed. Extra options are
+// applied on top of the profile.
+func Public(opts ...Option) *Policy {
+	base := []Option{WithHiddenServerMessages(), WithHiddenServerDetails(), WithCauses(false)}
+	return New(append(base, opts...)...)
+}
+
+// Internal returns the profile for trusted callers: messages and causes are
+// exposed, only sensitive keys are dropped. Extra options are applied on top
+// of the profile.
+func Internal(opts ...Option) *Policy {
+	return New(append([]Option{WithCauses(true)}, opts...)...)
+}
+
+// RedactMessage implements apis.Redactor.
+func (p *Policy) RedactMessage(c code.Code, _ reason.Reason, msg string) string {
+	if p == nil {
+		return msg
+	}
+	if _, ok := p.hidden[c]; ok || (p.hideServer && code.Info(c).ServerFault) {
+		return p.generic
+	}
+	return msg
+}
+
+// RedactDetail implements apis.Redactor.
+//
+// Details of sensitive keys (see derrors.KeySensitive) are always dropped,
+// regardless of the profile. With WithHiddenServerDetails, c decides whether
+// non-public details survive.
+func (p *Policy) RedactDetail(c code.Code, d apis.Detail) (apis.Detail, bool) {
+	vis := derrors.KeyVisibility(d.Field)
+	if vis == derrors.VisibilitySensitive {
+		return apis.Detail{}, false
+	}
+	if p == nil {
+		return d, true
+	}
+	if p.hideServerDetails && vis != derrors.VisibilityPublic && code.Info(c).ServerFault {
+		return apis.Detail{}, false
+	}
+	if _, ok := p.drop[d.Field]; ok {
+		return apis.Detail{}, false
+	}
+	if _, ok := p.mask[d.Field]; ok && len(d.Info) > 0 {
+		info := maps.Clone(d.Info)
+		for k := range info {
+			info[k] = p.maskStr
+		}
+		d.Info = info
+	}
+	return d, true
+}
+
+// ExposeCauses implements apis.Redactor.
+func (p *Policy) ExposeCauses(code.Code) bool {
+	return p != nil && p.causes
+}
@@ -0,0 +1,110 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package redact
+
+import (
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+	"dirpx.dev/derrors/v2/code"
+)
+
+var secretKey = derrors.NewKey[string]("redact_test_secret", derrors.KeySensitive())
+
+func TestPublic(t *testing.T) {
+	p := Public(WithMaskedKeys("email"), WithDroppedKeys("host"))
+
+	if got := p.RedactMessage(code.Internal, "", "pq: password auth failed"); got != DefaultGenericMessage {
+		t.Fatalf("internal message = %q", got)
+	}
+	if got := p.RedactMessage(code.NotFound, "", "user not found"); got != "user not found" {
+		t.Fatalf("not_found message = %q", got)
+	}
+	if p.ExposeCauses(code.Invalid) {
+		t.Fatal("public profile must not expose causes")
+	}
+
+	d, ok := p.RedactDetail(code.Invalid, apis.Detail{Field: "email", Info: map[string]string{"value": "a@b.c"}})
+	if !ok || d.Info["value"] != DefaultMask {
+		t.Fatalf("masked detail = %+v, %v", d, ok)
+	}
+	if _, ok := p.RedactDetail(code.Invalid, apis.Detail{Field: "host"}); ok {
+		t.Fatal("dropped key must be removed")
+	}
+	if _, ok := p.RedactDetail(code.Invalid, apis.Detail{Field: "redact_test_secret"}); ok {
+		t.Fatal("sensitive key must be removed")
+	}
+}
+
+var publicKey = derrors.NewKey[string]("redact_test_public", derrors.KeyPublic())
+
+func TestPublic_HidesEveryServerFaultMessage(t *testing.T) {
+	p := Public()
+	// The hidden set follows code.Info(c).ServerFault, so transient server
+	// conditions are hidden too, while client-side quota codes are not.
+	for _, c := range []code.Code{code.Internal, code.Unavailable, code.DependencyFailed,
+		code.Timeout, code.NotReady, code.Draining, code.Overloaded} {
+		if got := p.RedactMessage(c, "", "pool db-7 exhausted"); got != DefaultGenericMessage {
+			t.Errorf("%s message = %q, want hidden", c, got)
+		}
+	}
+	for _, c := range []code.Code{code.RateLimited, code.Throttled, code.PermissionDenied} {
+		if got := p.RedactMessage(c, "", "slow down"); got != "slow down" {
+			t.Errorf("%s message = %q, want kept", c, got)
+		}
+	}
+}
+
+func TestPublic_DetailsByCodeClass(t *testing.T) {
+	p := Public()
+	host := apis.Detail{Field: "host", Info: map[string]string{"value": "db:5432"}}
+
+	if _, ok := p.RedactDetail(code.Invalid, host); !ok {
+		t.Fatal("client error details must be kept")
+	}
+	if _, ok := p.RedactDetail(code.Unavailable, host); ok {
+		t.Fatal("server error details must be dropped")
+	}
+	if _, ok := p.RedactDetail(code.Unavailable, apis.Detail{Field: publicKey.Name()}); !ok {
+		t.Fatal("details declared public must survive server errors")
+	}
+	if _, ok := Internal().RedactDetail(code.Unavailable, host); !ok {
+		t.Fatal("internal profile must keep server error details")
+	}
+}
+
+func TestInternal(t *testing.T) {
+	p := Internal()
+	if got := p.RedactMessage(code.Internal, "", "pq: password auth failed"); got != "pq: password auth failed" {
+		t.Fatalf("internal message = %q", got)
+	}
+	if !p.ExposeCauses(code.Internal) {
+		t.Fatal("internal profile must expose causes")
+	}
+	if _, ok := p.RedactDetail(code.Internal, apis.Detail{Field: secretKey.Name()}); ok {
+		t.Fatal("sensitive key must be removed in every profile")
+	}
+}
+
+func TestMaskDoesNotMutateInput(t *testing.T) {
+	info := map[string]string{"value": "a@b.c"}
+	_, _ = New(WithMaskedKeys("email")).RedactDetail(code.Invalid, apis.Detail{Field: "email", Info: info})
+	if info["value"] != "a@b.c" {
+		t.Fatal("RedactDetail must not mutate the input detail")
+	}
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package slogx integrates derrors with log/slog.
+//
+// *derrors.Error already implements slog.LogValuer, so logging it directly
+// produces a structured group. Errors usually travel wrapped, though
+// (fmt.Errorf("...: %w", err), errors.Join), and then slog only sees a plain
+// error and logs its text.
+//
+// Handler closes that gap: it wraps any slog.Handler, looks at every attribute
+// holding an error, and when a *derrors.Error is found anywhere in the chain it
+// replaces the attribute with the expanded group. Given an apis.Mapper, it also
+// adds the resolved transport statuses:
+//
+//	logger := slog.New(slogx.NewHandler(
+//	    slog.NewJSONHandler(os.Stderr, nil),
+//	    slogx.WithMapper(m),
+//	))
+//	logger.Error("request failed", "err", err)
+//	// {"msg":"request failed","err":{"code":"unavailable",...,"http_status":503,"grpc_code":"Unavailable"}}
+package slogx
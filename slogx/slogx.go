@@ -0,0 +1,134 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/apis"
+)
+
+// Ensure Handler is a drop-in slog.Handler.
+var _ slog.Handler = (*Handler)(nil)
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithMapper makes the handler add "http_status" and "grpc_code" to every
+// expanded error, resolved through m.
+func WithMapper(m apis.Mapper) Option {
+	return func(h *Handler) { h.mapper = m }
+}
+
+// Handler is a slog.Handler wrapper that expands derrors errors found in
+// record and logger attributes. All other attributes are passed through
+// unchanged.
+type Handler struct {
+	next   slog.Handler
+	mapper apis.Mapper
+}
+
+// NewHandler wraps next with derrors-aware attribute expansion.
+func NewHandler(next slog.Handler, opts ...Option) *Handler {
+	h := &Handler{next: next}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Enabled implements slog.Handler by delegating to the wrapped handler.
+func (h *Handler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+// Handle implements slog.Handler. It rebuilds the record with expanded error
+// attributes and passes it on.
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.expand(a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs implements slog.Handler. Logger-level attributes are expanded
+// once, here, rather than on every record.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = h.expand(a)
+	}
+	return &Handler{next: h.next.WithAttrs(out), mapper: h.mapper}
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{next: h.next.WithGroup(name), mapper: h.mapper}
+}
+
+// expand replaces an error-valued attribute with the derrors group when the
+// error chain contains a *derrors.Error. Groups are expanded recursively.
+func (h *Handler) expand(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		in := a.Value.Group()
+		out := make([]slog.Attr, len(in))
+		for i, ga := range in {
+			out[i] = h.expand(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+	case slog.KindAny, slog.KindLogValuer:
+		err, ok := a.Value.Any().(error)
+		if !ok {
+			return a
+		}
+		var de *derrors.Error
+		if !errors.As(err, &de) {
+			return a
+		}
+		return slog.Attr{Key: a.Key, Value: h.errorValue(err, de)}
+	default:
+		return a
+	}
+}
+
+// errorValue builds the expanded group for de, found inside err.
+func (h *Handler) errorValue(err error, de *derrors.Error) slog.Value {
+	base := de.LogValue().Group()
+	attrs := make([]slog.Attr, 0, len(base)+3)
+	attrs = append(attrs, base...)
+	// Keep the outer text when the derrors error was wrapped, so context
+	// added by fmt.Errorf is not lost. A type assertion is used rather than
+	// comparing err with de, so no interface comparison is involved when
+	// err's dynamic type is not comparable (e.g. a slice-based multi-error).
+	if _, direct := err.(*derrors.Error); !direct {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if h.mapper != nil {
+		st := h.mapper.Status(de.Code, de.Reason)
+		attrs = append(attrs,
+			slog.Int("http_status", st.HTTP),
+			slog.String("grpc_code", st.GRPC.String()),
+		)
+	}
+	return slog.GroupValue(attrs...)
+}
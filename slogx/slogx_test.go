@@ -0,0 +1,131 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package slogx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"dirpx.dev/derrors/v2"
+	"dirpx.dev/derrors/v2/code"
+	"dirpx.dev/derrors/v2/mapper"
+	"dirpx.dev/derrors/v2/reason"
+)
+
+func logJSON(t *testing.T, h func(slog.Handler) slog.Handler, args ...any) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	logger := slog.New(h(slog.NewJSONHandler(&buf, nil)))
+	logger.Error("failed", args...)
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("decode log line %q: %v", buf.String(), err)
+	}
+	return out
+}
+
+func TestError_LogValue_Group(t *testing.T) {
+	de := derrors.E(code.Unavailable, "db is down",
+		derrors.WithReasonOption(reason.MustParse("storage.pg.connect_timeout")),
+		derrors.WithDetailOption("host", "db:5432"),
+		derrors.WithCauseOption(derrors.E(code.Timeout, "dial").WithCause(errors.New("i/o timeout"))),
+	)
+	out := logJSON(t, func(h slog.Handler) slog.Handler { return h }, "err", de)
+
+	g, ok := out["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("err must be a group, got %#v", out["err"])
+	}
+	if g["code"] != "unavailable" || g["reason"] != "storage.pg.connect_timeout" || g["message"] != "db is down" {
+		t.Fatalf("unexpected group: %#v", g)
+	}
+	if d := g["details"].(map[string]any); d["host"] != "db:5432" {
+		t.Fatalf("details missing: %#v", g["details"])
+	}
+	cause := g["cause"].(map[string]any)
+	if cause["code"] != "timeout" {
+		t.Fatalf("nested *Error cause must be a derrors group: %#v", cause)
+	}
+	if inner := cause["cause"].(map[string]any); inner["message"] != "i/o timeout" {
+		t.Fatalf("plain cause must render as message group: %#v", inner)
+	}
+}
+
+func TestHandler_ExpandsWrappedErrorsWithStatus(t *testing.T) {
+	m, err := mapper.New()
+	if err != nil {
+		t.Fatalf("mapper.New: %v", err)
+	}
+	de := derrors.E(code.NotFound, "no user")
+	wrapped := fmt.Errorf("lookup: %w", de)
+
+	withMapper := func(h slog.Handler) slog.Handler { return NewHandler(h, WithMapper(m)) }
+	out := logJSON(t, withMapper, "err", wrapped, slog.Group("req", "err", de), "n", 1)
+
+	g, ok := out["err"].(map[string]any)
+	if !ok {
+		t.Fatalf("wrapped error must be expanded, got %#v", out["err"])
+	}
+	if g["code"] != "not_found" || g["error"] != "lookup: not_found: no user" {
+		t.Fatalf("unexpected group: %#v", g)
+	}
+	if g["http_status"] != float64(404) || g["grpc_code"] != "NotFound" {
+		t.Fatalf("mapped statuses missing: %#v", g)
+	}
+	nested := out["req"].(map[string]any)["err"].(map[string]any)
+	if nested["http_status"] != float64(404) {
+		t.Fatalf("errors inside groups must be expanded: %#v", nested)
+	}
+	if out["n"] != float64(1) {
+		t.Fatalf("other attributes must pass through: %#v", out)
+	}
+}
+
+// sliceErrors is a multi-error whose dynamic type is not comparable.
+type sliceErrors []error
+
+func (s sliceErrors) Error() string   { return fmt.Sprintf("%d errors", len(s)) }
+func (s sliceErrors) Unwrap() []error { return s }
+
+func TestHandler_NonComparableError(t *testing.T) {
+	err := sliceErrors{errors.New("x"), derrors.E(code.Conflict, "taken")}
+	out := logJSON(t, func(h slog.Handler) slog.Handler { return NewHandler(h) }, "err", err)
+
+	g, ok := out["err"].(map[string]any)
+	if !ok || g["code"] != "conflict" || g["error"] != "2 errors" {
+		t.Fatalf("unexpected group: %#v", out["err"])
+	}
+}
+
+func TestHandler_WithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil))).
+		With("err", fmt.Errorf("ctx: %w", derrors.E(code.Invalid, "bad")))
+	logger.Info("x")
+
+	var out map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if g, ok := out["err"].(map[string]any); !ok || g["code"] != "invalid" {
+		t.Fatalf("logger attrs must be expanded: %#v", out["err"])
+	}
+}
@@ -0,0 +1,116 @@
+/*
+   Copyright 2025 The DIRPX Authors
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package derrors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// DefaultStackDepth is the number of frames recorded by WithStackOption when
+// stacks are not enabled globally.
+const DefaultStackDepth = 32
+
+// stackDepth is the global capture depth; 0 disables capturing.
+// It is read on every E call, so it must stay a single atomic load.
+var stackDepth atomic.Int32
+
+// EnableStacks turns on stack capture for E and WithCause, recording at most
+// depth frames per error. A depth <= 0 disables capturing again.
+//
+// Capturing is off by default so that the hot path stays allocation-light.
+// When enabled, only raw program counters are stored; they are symbolized
+// lazily by StackTrace or the %+v verb.
+//
+// It is safe to call EnableStacks concurrently with error construction, but
+// it is meant to be called once during program start-up.
+func EnableStacks(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+	stackDepth.Store(int32(depth))
+}
+
+// StacksEnabled reports whether stack capture is currently enabled globally.
+func StacksEnabled() bool { return stackDepth.Load() > 0 }
+
+// WithStackOption forces stack capture for a single error, regardless of the
+// global EnableStacks setting. If the error already carries a stack, it is
+// kept as-is.
+// Intended to be used with E(...).
+func WithStackOption() Option {
+	return func(e *Error) *Error {
+		if e.stack != nil {
+			return e
+		}
+		cp := *e
+		// 0: runtime.Callers, 1: captureStack, 2: this closure, 3: its caller.
+		// E and ESkip replace this stack with one starting at their caller.
+		cp.stack = captureStack(3, forcedStackDepth())
+		return &cp
+	}
+}
+
+// forcedStackDepth is the depth recorded by WithStackOption.
+func forcedStackDepth() int {
+	depth := DefaultStackDepth
+	if d := int(stackDepth.Load()); d > depth {
+		depth = d
+	}
+	return depth
+}
+
+// StackTrace returns the frames recorded when the error was created, innermost
+// first. It returns nil when no stack was captured.
+//
+// Frames are symbolized on every call; callers that need them repeatedly
+// should keep the result.
+func (e *Error) StackTrace() []runtime.Frame {
+	if e == nil || len(e.stack) == 0 {
+		return nil
+	}
+	frames := runtime.CallersFrames(e.stack)
+	out := make([]runtime.Frame, 0, len(e.stack))
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+		if !more {
+			break
+		}
+	}
+	return out
+}
+
+// captureStack records up to depth program counters, skipping the given
+// number of frames as defined by runtime.Callers.
+func captureStack(skip, depth int) []uintptr {
+	pcs := make([]uintptr, depth)
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+	return pcs[:n:n]
+}
+
+// writeStack prints frames in the conventional "function\n\tfile:line" layout.
+func writeStack(w io.Writer, frames []runtime.Frame) {
+	for _, f := range frames {
+		_, _ = fmt.Fprintf(w, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+}